@@ -0,0 +1,31 @@
+module github.com/All-Hands-AI/OpenHands/launcher
+
+go 1.21
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/docker/docker v24.0.9+incompatible
+	github.com/docker/go-connections v0.5.0
+	github.com/docker/go-units v0.5.0
+	github.com/opencontainers/image-spec v1.1.0
+	github.com/zalando/go-keyring v0.2.8
+	golang.org/x/term v0.18.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/Azure/go-ansiterm v0.0.0-20210617225240-d185dfc1b5a1 // indirect
+	github.com/Microsoft/go-winio v0.4.14 // indirect
+	github.com/danieljoos/wincred v1.2.3 // indirect
+	github.com/distribution/reference v0.5.0 // indirect
+	github.com/docker/distribution v2.8.3+incompatible // indirect
+	github.com/godbus/dbus/v5 v5.2.2 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/moby/term v0.5.0 // indirect
+	github.com/morikuni/aec v1.0.0 // indirect
+	github.com/opencontainers/go-digest v1.0.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+	golang.org/x/time v0.5.0 // indirect
+	gotest.tools/v3 v3.5.2 // indirect
+)
@@ -0,0 +1,251 @@
+package internal
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// archiveFormatVersion is bumped when the bundle layout changes, so load can
+// refuse archives it does not understand instead of misreading them.
+const archiveFormatVersion = 1
+
+// archiveMeta is the metadata.json entry of a session bundle.
+type archiveMeta struct {
+	FormatVersion   int       `json:"format_version"`
+	ConversationID  string    `json:"conversation_id"`
+	Workspace       string    `json:"workspace"`
+	LauncherVersion string    `json:"launcher_version"`
+	CreatedAt       time.Time `json:"created_at"`
+	Events          int       `json:"events"`
+}
+
+// CmdArchive implements `openhands archive save|load`: save bundles a
+// conversation's events, the merged settings, the workspace's uncommitted
+// diff and metadata into one .tar.gz for record keeping or moving between
+// machines; load unpacks a bundle and can re-apply the workspace diff. The
+// server has no event-import API, so load restores the materials (events,
+// diff, settings) rather than a live conversation.
+func CmdArchive(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: openhands archive save|load [flags]")
+		return 2
+	}
+	switch args[0] {
+	case "save":
+		return archiveSave(args[1:])
+	case "load":
+		return archiveLoad(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "openhands: unknown archive command %q (want save or load)\n", args[0])
+		return 2
+	}
+}
+
+func archiveSave(args []string) int {
+	fs := flag.NewFlagSet("archive save", flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	workspace := fs.String("workspace", ".", "workspace whose diff to include")
+	out := fs.String("o", "", "output file (default openhands-<conversation>.tar.gz)")
+	fs.Parse(args)
+
+	client := newEventsClient(fmt.Sprintf("http://localhost:%d", *port))
+	convID := fs.Arg(0)
+	var err error
+	if convID == "" {
+		if convID, err = client.LatestConversation(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	events, err := client.Events(convID, -1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: fetching events: %v\n", err)
+		return 1
+	}
+	absWorkspace, err := filepath.Abs(*workspace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	path := *out
+	if path == "" {
+		path = fmt.Sprintf("openhands-%s.tar.gz", convID)
+	}
+	if err := writeArchive(path, convID, absWorkspace, events); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Saved conversation %s (%d events) to %s\n", convID, len(events), path)
+	return 0
+}
+
+// writeArchive assembles the bundle.
+func writeArchive(path, convID, workspace string, events []Event) error {
+	raws := make([]map[string]interface{}, 0, len(events))
+	for i := range events {
+		raws = append(raws, events[i].Raw)
+	}
+	eventsJSON, err := json.MarshalIndent(raws, "", "  ")
+	if err != nil {
+		return err
+	}
+	meta := archiveMeta{
+		FormatVersion:   archiveFormatVersion,
+		ConversationID:  convID,
+		Workspace:       workspace,
+		LauncherVersion: Version,
+		CreatedAt:       time.Now().UTC(),
+		Events:          len(events),
+	}
+	metaJSON, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	var settingsJSON []byte
+	if merged, _, err := LoadMergedConfig(workspace); err == nil {
+		settingsJSON, _ = json.MarshalIndent(merged, "", "  ")
+	}
+	// The diff covers tracked, uncommitted changes - what the agent did to
+	// the workspace that is not yet in git history. Failure (not a git
+	// repo) just leaves the entry out.
+	diff, _ := exec.Command("git", "-C", workspace, "diff", "HEAD").Output()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{"metadata.json", metaJSON},
+		{"events.json", eventsJSON},
+		{"settings.json", settingsJSON},
+		{"workspace.diff", diff},
+	}
+	for _, e := range entries {
+		if len(e.data) == 0 {
+			continue
+		}
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Size: int64(len(e.data)), ModTime: meta.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func archiveLoad(args []string) int {
+	fs := flag.NewFlagSet("archive load", flag.ExitOnError)
+	dest := fs.String("d", "", "directory to unpack into (default openhands-<conversation>/)")
+	applyDiff := fs.String("apply-diff", "", "additionally apply the bundled workspace diff to this directory with git apply")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands archive load [flags] FILE\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	meta, files, err := readArchive(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	dir := *dest
+	if dir == "" {
+		dir = "openhands-" + meta.ConversationID
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Printf("Unpacked conversation %s (%d events, saved %s) into %s\n",
+		meta.ConversationID, meta.Events, meta.CreatedAt.Format("2006-01-02"), dir)
+
+	if *applyDiff != "" {
+		diff, ok := files["workspace.diff"]
+		if !ok {
+			fmt.Fprintln(os.Stderr, "openhands: the archive contains no workspace diff")
+			return 1
+		}
+		cmd := exec.Command("git", "-C", *applyDiff, "apply")
+		cmd.Stdin = strings.NewReader(string(diff))
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: applying diff: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Applied the workspace diff to %s\n", *applyDiff)
+	}
+	return 0
+}
+
+// readArchive parses a bundle and validates its format version.
+func readArchive(path string) (*archiveMeta, map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s is not a gzip archive: %w", path, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[filepath.Base(hdr.Name)] = data
+	}
+	raw, ok := files["metadata.json"]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s has no metadata.json; not an openhands archive", path)
+	}
+	var meta archiveMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, nil, fmt.Errorf("parsing metadata.json: %w", err)
+	}
+	if meta.FormatVersion > archiveFormatVersion {
+		return nil, nil, fmt.Errorf("archive format v%d is newer than this launcher understands (v%d); upgrade first", meta.FormatVersion, archiveFormatVersion)
+	}
+	return &meta, files, nil
+}
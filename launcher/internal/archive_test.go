@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.tar.gz")
+	events := []Event{
+		{ID: 1, Source: "user", Action: "message", Raw: map[string]interface{}{"id": 1.0, "action": "message"}},
+		{ID: 2, Source: "agent", Action: "run", Raw: map[string]interface{}{"id": 2.0, "action": "run"}},
+	}
+	if err := writeArchive(path, "conv-42", dir, events); err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+
+	meta, files, err := readArchive(path)
+	if err != nil {
+		t.Fatalf("readArchive: %v", err)
+	}
+	if meta.ConversationID != "conv-42" || meta.Events != 2 {
+		t.Errorf("meta = %+v", meta)
+	}
+	if meta.FormatVersion != archiveFormatVersion {
+		t.Errorf("FormatVersion = %d", meta.FormatVersion)
+	}
+	if _, ok := files["events.json"]; !ok {
+		t.Error("events.json missing from archive")
+	}
+}
+
+func TestReadArchiveMissingFile(t *testing.T) {
+	if _, _, err := readArchive(filepath.Join(t.TempDir(), "missing.tar.gz")); err == nil {
+		t.Error("missing file should error")
+	}
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+// The audit log is an append-only JSONL file in the state directory. Each
+// record carries the SHA-256 of the previous record, forming a hash chain so
+// after-the-fact edits are detectable with `openhands audit list`.
+
+const auditFileName = "audit.jsonl"
+
+// AuditRecord is one audit log entry.
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	User       string    `json:"user"`
+	Event      string    `json:"event"` // "launch" or "exit"
+	Workspace  string    `json:"workspace,omitempty"`
+	Model      string    `json:"model,omitempty"`
+	AppImage   string    `json:"app_image,omitempty"`
+	AppDigest  string    `json:"app_digest,omitempty"`
+	Runtime    string    `json:"runtime_image,omitempty"`
+	ExitStatus *int64    `json:"exit_status,omitempty"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// computeHash hashes the record with its Hash field cleared.
+func (r *AuditRecord) computeHash() (string, error) {
+	clone := *r
+	clone.Hash = ""
+	raw, err := json.Marshal(&clone)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func auditPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, auditFileName), nil
+}
+
+// appendAudit chains and appends one record. Audit failures are reported by
+// the caller but never abort a launch.
+func appendAudit(rec *AuditRecord) error {
+	path, err := auditPath()
+	if err != nil {
+		return err
+	}
+	records, _, err := readAudit(path)
+	if err != nil {
+		return err
+	}
+	rec.Time = time.Now().UTC()
+	if u, err := user.Current(); err == nil {
+		rec.User = u.Username
+	}
+	if len(records) > 0 {
+		rec.PrevHash = records[len(records)-1].Hash
+	}
+	if rec.Hash, err = rec.computeHash(); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(raw, '\n'))
+	return err
+}
+
+// readAudit loads all records and returns the index of the first record
+// whose hash chain does not verify, or -1 if the log is intact.
+func readAudit(path string) ([]AuditRecord, int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, -1, nil
+	}
+	if err != nil {
+		return nil, -1, err
+	}
+	defer f.Close()
+
+	var records []AuditRecord
+	broken := -1
+	prevHash := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, len(records), fmt.Errorf("%s: malformed record %d: %w", path, len(records)+1, err)
+		}
+		if broken == -1 {
+			want, err := rec.computeHash()
+			if err != nil {
+				return nil, -1, err
+			}
+			if rec.Hash != want || rec.PrevHash != prevHash {
+				broken = len(records)
+			}
+		}
+		prevHash = rec.Hash
+		records = append(records, rec)
+	}
+	return records, broken, scanner.Err()
+}
+
+// auditLaunch records the start of a session.
+func auditLaunch(cfg *Config, appDigest string) {
+	rec := &AuditRecord{
+		Event:     "launch",
+		Workspace: cfg.Workspace,
+		Model:     cfg.LLM.Model,
+		AppImage:  cfg.AppImage(),
+		AppDigest: appDigest,
+		Runtime:   cfg.RuntimeImage(),
+	}
+	if err := appendAudit(rec); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: could not write audit log: %v\n", err)
+	}
+}
+
+// auditExit records the end of a session with its exit status.
+func auditExit(cfg *Config, status int64) {
+	rec := &AuditRecord{
+		Event:      "exit",
+		Workspace:  cfg.Workspace,
+		ExitStatus: &status,
+	}
+	if err := appendAudit(rec); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: could not write audit log: %v\n", err)
+	}
+}
+
+// CmdAudit implements `openhands audit list`.
+func CmdAudit(args []string) int {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	fs.Parse(args)
+	if fs.Arg(0) != "list" {
+		fmt.Fprintln(os.Stderr, "usage: openhands audit list")
+		return 2
+	}
+	path, err := auditPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	records, broken, err := readAudit(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if len(records) == 0 {
+		fmt.Println("The audit log is empty.")
+		return 0
+	}
+	for i, rec := range records {
+		line := fmt.Sprintf("%s  %-8s %-6s %s", rec.Time.Format(time.RFC3339), rec.User, rec.Event, rec.Workspace)
+		if rec.Event == "launch" && rec.AppDigest != "" {
+			line += "  " + rec.AppDigest
+		}
+		if rec.ExitStatus != nil {
+			line += fmt.Sprintf("  exit=%d", *rec.ExitStatus)
+		}
+		if broken != -1 && i >= broken {
+			line += "  [chain broken]"
+		}
+		fmt.Println(line)
+	}
+	if broken != -1 {
+		fmt.Fprintf(os.Stderr, "openhands: hash chain broken at record %d; entries from there on may have been altered\n", broken+1)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditChain(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if err := appendAudit(&AuditRecord{Event: "launch", Workspace: "/w"}); err != nil {
+		t.Fatalf("appendAudit: %v", err)
+	}
+	status := int64(0)
+	if err := appendAudit(&AuditRecord{Event: "exit", Workspace: "/w", ExitStatus: &status}); err != nil {
+		t.Fatalf("appendAudit: %v", err)
+	}
+
+	path, err := auditPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, broken, err := readAudit(path)
+	if err != nil {
+		t.Fatalf("readAudit: %v", err)
+	}
+	if len(records) != 2 || broken != -1 {
+		t.Fatalf("records = %d, broken = %d; want 2 intact records", len(records), broken)
+	}
+	if records[1].PrevHash != records[0].Hash {
+		t.Fatal("second record is not chained to the first")
+	}
+
+	// Tampering with the first record must break the chain at record 0.
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(raw), "/w", "/x", 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, broken, err = readAudit(path)
+	if err != nil {
+		t.Fatalf("readAudit after tamper: %v", err)
+	}
+	if broken != 0 {
+		t.Fatalf("broken = %d, want 0", broken)
+	}
+}
+
+func TestReadAuditMissingFile(t *testing.T) {
+	records, broken, err := readAudit(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil || records != nil || broken != -1 {
+		t.Fatalf("readAudit = (%v, %d, %v); want empty result", records, broken, err)
+	}
+}
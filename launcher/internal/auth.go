@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// secretStore abstracts the OS keychain (Keychain on macOS, Secret Service
+// on Linux, Credential Manager on Windows) so tests can substitute an
+// in-memory fake.
+type secretStore interface {
+	Set(name, secret string) error
+	Get(name string) (string, error)
+	Delete(name string) error
+}
+
+// keyringService is the service name the launcher's secrets live under in
+// the OS keychain.
+const keyringService = "openhands-cli"
+
+// llmKeyName is the entry name of the stored LLM API key.
+const llmKeyName = "llm-api-key"
+
+type osKeyring struct{}
+
+func (osKeyring) Set(name, secret string) error {
+	return keyring.Set(keyringService, name, secret)
+}
+
+func (osKeyring) Get(name string) (string, error) {
+	return keyring.Get(keyringService, name)
+}
+
+func (osKeyring) Delete(name string) error {
+	return keyring.Delete(keyringService, name)
+}
+
+// secrets is the keychain in use; tests swap in a fake.
+var secrets secretStore = osKeyring{}
+
+// credentialFilePath is the fallback location used when no keychain backend
+// is available - a headless Linux box without a Secret Service, typically.
+func credentialFilePath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials"), nil
+}
+
+// storeAPIKey writes the LLM API key into the OS keychain and returns a
+// human-readable description of where it ended up. Without a keychain
+// backend it falls back, with a warning, to a file under ~/.openhands
+// readable only by the user.
+func storeAPIKey(key string) (string, error) {
+	err := secrets.Set(llmKeyName, key)
+	if err == nil {
+		return "the OS keychain", nil
+	}
+	warnf("No OS keychain is available (%v); storing the key in a file readable only by you", err)
+	path, perr := credentialFilePath()
+	if perr != nil {
+		return "", perr
+	}
+	if err := os.WriteFile(path, []byte(key+"\n"), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// lookupAPIKey returns the stored LLM API key and where it came from, or
+// empty strings when nothing is stored. The keychain wins over the
+// fallback file.
+func lookupAPIKey() (key, source string) {
+	if v, err := secrets.Get(llmKeyName); err == nil && v != "" {
+		return v, "the OS keychain"
+	}
+	path, err := credentialFilePath()
+	if err != nil {
+		return "", ""
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", ""
+	}
+	if v := strings.TrimSpace(string(raw)); v != "" {
+		return v, path
+	}
+	return "", ""
+}
+
+// clearAPIKey removes the stored key from the keychain and the fallback
+// file, reporting whether anything was actually removed.
+func clearAPIKey() (bool, error) {
+	removed := false
+	if err := secrets.Delete(llmKeyName); err == nil {
+		removed = true
+	}
+	path, err := credentialFilePath()
+	if err != nil {
+		return removed, err
+	}
+	switch err := os.Remove(path); {
+	case err == nil:
+		removed = true
+	case !os.IsNotExist(err):
+		return removed, err
+	}
+	return removed, nil
+}
+
+// CmdAuth implements `openhands auth login|status|logout`: the LLM API key
+// lives in the OS keychain instead of a config file or shell history.
+// Launches pick the stored key up automatically whenever no
+// -api-key/$LLM_API_KEY/config-file value is present.
+func CmdAuth(args []string) int {
+	fs := flag.NewFlagSet("auth", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands auth login\n       openhands auth status\n       openhands auth logout\n\nlogin prompts for a provider, model and API key (not echoed) and stores\nthe key in the OS keychain. status shows what is stored, masked. logout\nremoves the stored key.\n")
+	}
+	fs.Parse(args)
+	var err error
+	switch fs.Arg(0) {
+	case "login":
+		err = authLogin(newTerminalPrompter())
+	case "status":
+		err = authStatus()
+	case "logout":
+		err = authLogout()
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// authLogin mirrors the setup wizard's prompts but stores the key in the
+// keychain and only the model name in the global config, so the config
+// file never holds the secret.
+func authLogin(p prompter) error {
+	if !interactiveSession() {
+		return fmt.Errorf("auth login needs an interactive terminal")
+	}
+	for i, m := range wizardModels {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, m.Label)
+	}
+	choice, err := p.Ask("Provider", "1")
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(choice))
+	if err != nil || n < 1 || n > len(wizardModels) {
+		return fmt.Errorf("invalid choice %q", choice)
+	}
+	model := wizardModels[n-1].Model
+	if model == "" {
+		if model, err = p.Ask("Model name (LiteLLM format, e.g. openai/local)", ""); err != nil {
+			return err
+		}
+		if model == "" {
+			return fmt.Errorf("a model name is required")
+		}
+	}
+	key, err := p.AskSecret("API key")
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return fmt.Errorf("an API key is required; keyless local servers are configured in the config file instead")
+	}
+	where, err := storeAPIKey(key)
+	if err != nil {
+		return err
+	}
+	if err := saveGlobalLLM(LLMConfig{Model: model}); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Logged in with %s; the API key is stored in %s.\n", model, where)
+	return nil
+}
+
+func authStatus() error {
+	key, source := lookupAPIKey()
+	if key == "" {
+		fmt.Println("No API key is stored; run `openhands auth login`.")
+		return nil
+	}
+	fmt.Printf("API key %s stored in %s\n", maskSecret(key), source)
+	return nil
+}
+
+func authLogout() error {
+	removed, err := clearAPIKey()
+	if err != nil {
+		return err
+	}
+	if !removed {
+		fmt.Fprintln(os.Stderr, "No stored API key to remove.")
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "Logged out; the stored API key was removed.")
+	return nil
+}
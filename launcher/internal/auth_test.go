@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeStore is an in-memory secretStore; a non-nil err makes every call
+// fail, simulating a machine without a keychain backend.
+type fakeStore struct {
+	m   map[string]string
+	err error
+}
+
+func (f *fakeStore) Set(name, secret string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.m == nil {
+		f.m = map[string]string{}
+	}
+	f.m[name] = secret
+	return nil
+}
+
+func (f *fakeStore) Get(name string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	v, ok := f.m[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func (f *fakeStore) Delete(name string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if _, ok := f.m[name]; !ok {
+		return errors.New("not found")
+	}
+	delete(f.m, name)
+	return nil
+}
+
+func swapSecrets(t *testing.T, s secretStore) {
+	t.Helper()
+	old := secrets
+	secrets = s
+	t.Cleanup(func() { secrets = old })
+}
+
+func TestStoreLookupClearAPIKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	swapSecrets(t, &fakeStore{})
+
+	if key, _ := lookupAPIKey(); key != "" {
+		t.Fatalf("lookupAPIKey on empty store = %q, want empty", key)
+	}
+	where, err := storeAPIKey("sk-test-1234")
+	if err != nil {
+		t.Fatalf("storeAPIKey: %v", err)
+	}
+	if where != "the OS keychain" {
+		t.Errorf("storeAPIKey stored in %q, want the OS keychain", where)
+	}
+	key, source := lookupAPIKey()
+	if key != "sk-test-1234" || source != "the OS keychain" {
+		t.Errorf("lookupAPIKey = %q, %q", key, source)
+	}
+	removed, err := clearAPIKey()
+	if err != nil || !removed {
+		t.Fatalf("clearAPIKey = %v, %v, want true, nil", removed, err)
+	}
+	if key, _ := lookupAPIKey(); key != "" {
+		t.Errorf("key still present after clear: %q", key)
+	}
+	if removed, _ := clearAPIKey(); removed {
+		t.Errorf("second clearAPIKey reported a removal")
+	}
+}
+
+func TestAPIKeyFileFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	swapSecrets(t, &fakeStore{err: errors.New("no backend")})
+
+	where, err := storeAPIKey("sk-fallback-5678")
+	if err != nil {
+		t.Fatalf("storeAPIKey: %v", err)
+	}
+	if !strings.HasSuffix(where, "credentials") {
+		t.Errorf("storeAPIKey stored in %q, want the credentials file", where)
+	}
+	st, err := os.Stat(where)
+	if err != nil {
+		t.Fatalf("stat fallback file: %v", err)
+	}
+	if perm := st.Mode().Perm(); perm != 0o600 {
+		t.Errorf("fallback file mode = %o, want 600", perm)
+	}
+	key, source := lookupAPIKey()
+	if key != "sk-fallback-5678" || source != where {
+		t.Errorf("lookupAPIKey = %q, %q, want key from %s", key, source, where)
+	}
+	removed, err := clearAPIKey()
+	if err != nil || !removed {
+		t.Fatalf("clearAPIKey = %v, %v, want true, nil", removed, err)
+	}
+	if _, err := os.Stat(where); !os.IsNotExist(err) {
+		t.Errorf("fallback file still exists after clear")
+	}
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// isWSL reports whether the launcher runs inside Windows Subsystem for
+// Linux, where Linux browsers usually do not exist and the URL must be
+// opened on the Windows host.
+func isWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	raw, err := os.ReadFile("/proc/version")
+	return err == nil && strings.Contains(strings.ToLower(string(raw)), "microsoft")
+}
+
+// browserCommands returns the candidate argv lists for opening url, in
+// preference order: the -browser flag, then $BROWSER, then platform
+// strategies. Under WSL the Windows-host openers come before xdg-open, since
+// a Linux browser is usually not installed there.
+func browserCommands(goos string, flagCmd, envCmd string, wsl bool, url string) [][]string {
+	var cmds [][]string
+	if flagCmd != "" {
+		cmds = append(cmds, append(strings.Fields(flagCmd), url))
+	}
+	if envCmd != "" {
+		cmds = append(cmds, append(strings.Fields(envCmd), url))
+	}
+	switch {
+	case wsl:
+		cmds = append(cmds,
+			[]string{"wslview", url},
+			// cmd.exe needs an empty title argument or it treats a
+			// quoted URL as the window title.
+			[]string{"cmd.exe", "/c", "start", "", url},
+			[]string{"powershell.exe", "Start-Process", url},
+		)
+	case goos == "darwin":
+		cmds = append(cmds, []string{"open", url})
+	case goos == "windows":
+		cmds = append(cmds, []string{"rundll32", "url.dll,FileProtocolHandler", url})
+	default:
+		cmds = append(cmds, []string{"xdg-open", url})
+	}
+	return cmds
+}
+
+// openBrowser opens the URL in the user's browser, trying each strategy in
+// order. When nothing works the URL is printed prominently instead, so the
+// user can open it by hand rather than digging it out of the logs.
+func openBrowser(url, flagCmd string) {
+	for _, argv := range browserCommands(runtime.GOOS, flagCmd, os.Getenv("BROWSER"), isWSL(), url) {
+		if _, err := exec.LookPath(argv[0]); err != nil {
+			continue
+		}
+		if err := exec.Command(argv[0], argv[1:]...).Start(); err == nil {
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nCould not open a browser. Open this URL yourself:\n\n    %s\n\n", url)
+}
@@ -0,0 +1,31 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBrowserCommands(t *testing.T) {
+	const url = "http://localhost:3000"
+
+	got := browserCommands("linux", "", "", false, url)
+	want := [][]string{{"xdg-open", url}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plain linux: got %v", got)
+	}
+
+	got = browserCommands("linux", "", "", true, url)
+	if len(got) != 3 || got[0][0] != "wslview" || got[1][0] != "cmd.exe" || got[2][0] != "powershell.exe" {
+		t.Errorf("WSL strategy order wrong: %v", got)
+	}
+
+	got = browserCommands("darwin", "firefox -P work", "chromium", false, url)
+	want = [][]string{
+		{"firefox", "-P", "work", url},
+		{"chromium", url},
+		{"open", url},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flag and env ordering: got %v", got)
+	}
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+)
+
+// CmdClean implements `openhands clean`: it removes exited containers the
+// launcher created, which accumulated on versions that did not auto-remove
+// (and still do under -keep-container). With -images it additionally offers
+// to remove app and runtime image tags other than the currently configured
+// one. Only containers and images carrying our label or repository names are
+// ever touched.
+func CmdClean(args []string) int {
+	fs := flag.NewFlagSet("clean", flag.ExitOnError)
+	images := fs.Bool("images", false, "also remove old app and runtime image tags, after confirmation")
+	tag := fs.String("tag", DefaultTag, "image tag to keep when removing old images")
+	yes := fs.Bool("yes", false, "skip the confirmation prompt for -images")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	if err := cleanContainers(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if *images {
+		if err := cleanImages(ctx, cli, &Config{Tag: *tag}, *yes); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// cleanContainers prunes stopped launcher-managed containers. Warm pool
+// members are excluded; `openhands pool` owns their lifecycle.
+func cleanContainers(ctx context.Context, cli *client.Client) error {
+	f := filters.NewArgs(
+		filters.Arg("label", LabelManaged+"=true"),
+		filters.Arg("label!", LabelPool),
+	)
+	report, err := cli.ContainersPrune(ctx, f)
+	if err != nil {
+		return fmt.Errorf("pruning containers: %w", err)
+	}
+	if len(report.ContainersDeleted) == 0 {
+		fmt.Println("No exited containers to remove.")
+		return nil
+	}
+	fmt.Printf("Removed %d container(s), reclaimed %s.\n",
+		len(report.ContainersDeleted), units.HumanSize(float64(report.SpaceReclaimed)))
+	return nil
+}
+
+// cleanImages removes app and runtime image tags other than the ones the
+// given config would use, after listing them and asking for confirmation.
+func cleanImages(ctx context.Context, cli *client.Client, cfg *Config, yes bool) error {
+	list, err := cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing images: %w", err)
+	}
+	keep := map[string]bool{cfg.AppImage(): true, cfg.RuntimeImage(): true}
+	var old []string
+	for _, img := range list {
+		for _, ref := range img.RepoTags {
+			if keep[ref] {
+				continue
+			}
+			if strings.HasPrefix(ref, DefaultAppImage+":") || strings.HasPrefix(ref, DefaultRuntimeImage+":") {
+				old = append(old, ref)
+			}
+		}
+	}
+	if len(old) == 0 {
+		fmt.Println("No old images to remove.")
+		return nil
+	}
+	fmt.Printf("Old images (keeping tag %s):\n", cfg.Tag)
+	for _, ref := range old {
+		fmt.Printf("  %s\n", ref)
+	}
+	if !yes && !confirm(fmt.Sprintf("Remove these %d image(s)?", len(old))) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+	removed := 0
+	for _, ref := range old {
+		// No Force: a tag still used by a running container is left alone.
+		if _, err := cli.ImageRemove(ctx, ref, types.ImageRemoveOptions{PruneChildren: true}); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: removing image %s: %v\n", ref, err)
+			continue
+		}
+		removed++
+	}
+	fmt.Printf("Removed %d image(s).\n", removed)
+	return nil
+}
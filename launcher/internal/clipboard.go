@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// copyToClipboard puts text on the system clipboard. It tries the platform's
+// native tool first and falls back to the OSC 52 terminal escape, which makes
+// the terminal emulator do the copying - the only thing that works over SSH
+// and inside tmux, where the launcher has no display to talk to.
+func copyToClipboard(text string) error {
+	if cmd := clipboardCmd(); cmd != nil {
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err == nil {
+			return nil
+		}
+	}
+	return copyViaOSC52(text)
+}
+
+// clipboardCmd returns the native clipboard writer for this platform, or nil
+// when none is installed.
+func clipboardCmd() *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("pbcopy")
+	case "windows":
+		return exec.Command("clip")
+	}
+	for _, candidate := range [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	} {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return exec.Command(candidate[0], candidate[1:]...)
+		}
+	}
+	return nil
+}
+
+// copyViaOSC52 writes the OSC 52 clipboard escape to the controlling
+// terminal. Most modern emulators (and tmux with set-clipboard on) honor it.
+func copyViaOSC52(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("no clipboard tool installed and no terminal for OSC 52: %w", err)
+	}
+	defer tty.Close()
+	_, err = fmt.Fprintf(tty, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
+}
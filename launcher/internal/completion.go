@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// CmdComplete implements the hidden `openhands __complete PREV CUR` helper
+// the shell-init completion functions call for dynamic candidates: instance
+// names after stop/logs/-name, local image tags after -tag, a curated model
+// list after -model, and flag names for anything starting with a dash. It
+// prints one candidate per line and never fails - an empty result just means
+// the shell falls back to file completion.
+func CmdComplete(args []string) int {
+	fs := flag.NewFlagSet("__complete", flag.ExitOnError)
+	fs.Parse(args)
+	for _, c := range completions(fs.Arg(0), fs.Arg(1)) {
+		fmt.Println(c)
+	}
+	return 0
+}
+
+// completions picks the candidate set from the word before the cursor.
+func completions(prev, cur string) []string {
+	switch strings.TrimLeft(prev, "-") {
+	case "stop", "logs", "shell", "attach", "name":
+		return matchPrefix(instanceNames(), cur)
+	case "model":
+		return matchPrefix(commonModels, cur)
+	case "tag":
+		return matchPrefix(localAppTags(), cur)
+	}
+	if strings.HasPrefix(cur, "-") {
+		return matchPrefix(launchFlagNames(), cur)
+	}
+	return nil
+}
+
+func matchPrefix(candidates []string, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// commonModels is a short curated list for -model completion; anything can
+// still be typed, this only covers the names people reach for first.
+var commonModels = []string{
+	"anthropic/claude-sonnet-4-20250514",
+	"anthropic/claude-3-5-sonnet-20241022",
+	"openai/gpt-4o",
+	"openai/gpt-4o-mini",
+	"gemini/gemini-1.5-pro",
+	"deepseek/deepseek-chat",
+}
+
+// instanceNames lists registered instances from the launch registry. The
+// registry is a local file, so this stays fast and works with the daemon
+// down; stale entries cost nothing worse than a useless candidate.
+func instanceNames() []string {
+	records, err := loadRegistry()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(records))
+	for _, rec := range records {
+		names = append(names, rec.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// launchFlagNames collects every launch flag, dash-prefixed.
+func launchFlagNames() []string {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	RegisterLaunchFlags(fs, &Config{})
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, "-"+f.Name) })
+	sort.Strings(names)
+	return names
+}
+
+// tagCacheTTL bounds how stale the cached tag list may be before the daemon
+// is asked again.
+const tagCacheTTL = 10 * time.Minute
+
+func tagCachePath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cli", "completion-tags"), nil
+}
+
+// localAppTags lists the tags of locally present app images. The result is
+// cached on disk because completion runs on every keystroke, and the daemon
+// query is bounded to a second so a stopped daemon only costs that once.
+func localAppTags() []string {
+	path, err := tagCachePath()
+	if err == nil {
+		if st, err := os.Stat(path); err == nil && time.Since(st.ModTime()) < tagCacheTTL {
+			data, err := os.ReadFile(path)
+			if err == nil {
+				return strings.Fields(string(data))
+			}
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cli, err := NewDockerClient()
+	if err != nil {
+		return nil
+	}
+	defer cli.Close()
+	images, err := cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, img := range images {
+		for _, rt := range img.RepoTags {
+			if tag, ok := strings.CutPrefix(rt, DefaultAppImage+":"); ok {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	if path != "" {
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		os.WriteFile(path, []byte(strings.Join(tags, "\n")), 0o644)
+	}
+	return tags
+}
@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletionsModels(t *testing.T) {
+	got := completions("-model", "anthropic/")
+	if len(got) == 0 {
+		t.Fatal("no model completions for anthropic/ prefix")
+	}
+	for _, c := range got {
+		if !strings.HasPrefix(c, "anthropic/") {
+			t.Errorf("completion %q does not match the prefix", c)
+		}
+	}
+}
+
+func TestCompletionsFlags(t *testing.T) {
+	got := completions("", "-por")
+	if len(got) != 2 || got[0] != "-port" || got[1] != "-port-retry" {
+		t.Errorf("completions(\"\", \"-por\") = %v, want [-port -port-retry]", got)
+	}
+	if got := completions("", "plainword"); got != nil {
+		t.Errorf("non-flag word should yield no candidates, got %v", got)
+	}
+}
@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeService is the subset of the compose service schema that
+// buildDockerArgs exercises. Fields marshal in declaration order, so keep
+// the layout readable: identity first, then environment, mounts, networking,
+// limits.
+type composeService struct {
+	Image         string                 `yaml:"image"`
+	ContainerName string                 `yaml:"container_name"`
+	StdinOpen     bool                   `yaml:"stdin_open"`
+	Tty           bool                   `yaml:"tty"`
+	Labels        []string               `yaml:"labels,omitempty"`
+	Environment   []string               `yaml:"environment,omitempty"`
+	Volumes       []string               `yaml:"volumes,omitempty"`
+	NetworkMode   string                 `yaml:"network_mode,omitempty"`
+	Networks      []string               `yaml:"networks,omitempty"`
+	Ports         []string               `yaml:"ports,omitempty"`
+	ExtraHosts    []string               `yaml:"extra_hosts,omitempty"`
+	User          string                 `yaml:"user,omitempty"`
+	ShmSize       string                 `yaml:"shm_size,omitempty"`
+	MemLimit      string                 `yaml:"mem_limit,omitempty"`
+	CPUs          float64                `yaml:"cpus,omitempty"`
+	Ulimits       map[string]interface{} `yaml:"ulimits,omitempty"`
+	SecurityOpt   []string               `yaml:"security_opt,omitempty"`
+	CapDrop       []string               `yaml:"cap_drop,omitempty"`
+	CapAdd        []string               `yaml:"cap_add,omitempty"`
+	Command       []string               `yaml:"command,omitempty"`
+}
+
+type composeNetwork struct {
+	External bool `yaml:"external"`
+}
+
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+	Networks map[string]composeNetwork  `yaml:"networks,omitempty"`
+}
+
+// buildComposeFile renders the same container buildDockerArgs would run as a
+// compose file. The one deliberate difference is the LLM API key: compose
+// files get checked in, so the key is referenced as ${LLM_API_KEY} and
+// resolved by compose from the shell or a .env file at `up` time.
+func buildComposeFile(cfg *Config) *composeFile {
+	svc := &composeService{
+		Image:         cfg.AppImage(),
+		ContainerName: cfg.containerName(),
+		StdinOpen:     true,
+		Tty:           true,
+		Labels:        launchLabelPairs(cfg),
+		User:          cfg.User,
+		ShmSize:       cfg.ShmSize,
+		MemLimit:      cfg.Memory,
+		CPUs:          cfg.CPUs,
+		SecurityOpt:   cfg.SecurityOpts,
+		Command:       containerCmd(cfg),
+	}
+	managedEnv := containerEnv(cfg)
+	for _, env := range managedEnv {
+		if strings.HasPrefix(env, "LLM_API_KEY=") || strings.HasPrefix(env, "LLM_API_KEY_FILE=") {
+			env = "LLM_API_KEY=${LLM_API_KEY}"
+		}
+		svc.Environment = append(svc.Environment, env)
+	}
+	// Forwarded shell variables become interpolations, like the API key,
+	// so the rendered file carries no values from this shell.
+	for _, k := range forwardedEnvKeys(cfg, managedEnv) {
+		svc.Environment = append(svc.Environment, k+"=${"+k+"}")
+	}
+	if sock := socketBind(currentEngine); sock != "" && !cfg.NoDockerSock {
+		svc.Volumes = append(svc.Volumes, sock)
+	}
+	if !useSandboxVolumes(cfg) {
+		svc.Volumes = append(svc.Volumes, hostMountPath(cfg.mountWorkspace())+":"+WorkspaceMountPath+cfg.workspaceBindSuffix())
+	}
+	if cfg.stateDir != "" {
+		svc.Volumes = append(svc.Volumes, hostMountPath(cfg.stateDir)+":"+StateMountPath)
+	}
+	svc.Volumes = append(svc.Volumes, cfg.ExtraMounts...)
+	if cfg.ServerConfig != "" {
+		svc.Volumes = append(svc.Volumes, hostMountPath(cfg.ServerConfig)+":/app/config.toml:ro")
+	}
+	out := &composeFile{Services: map[string]*composeService{"openhands": svc}}
+	switch cfg.Network {
+	case "host":
+		svc.NetworkMode = "host"
+	case "":
+	default:
+		// A -network names an existing docker network; compose has to declare
+		// it as external rather than creating its own.
+		svc.Networks = []string{cfg.Network}
+		out.Networks = map[string]composeNetwork{cfg.Network: {External: true}}
+	}
+	if cfg.Network != "host" {
+		svc.Ports = []string{fmt.Sprintf("%s:%d:%d", cfg.Bind, cfg.Port, DefaultPort)}
+		svc.ExtraHosts = []string{"host.docker.internal:host-gateway"}
+	}
+	for _, ul := range cfg.Ulimits {
+		name, limit, ok := strings.Cut(ul, "=")
+		if !ok {
+			continue
+		}
+		if svc.Ulimits == nil {
+			svc.Ulimits = map[string]interface{}{}
+		}
+		if soft, hard, both := strings.Cut(limit, ":"); both {
+			s, _ := strconv.Atoi(soft)
+			h, _ := strconv.Atoi(hard)
+			svc.Ulimits[name] = map[string]int{"soft": s, "hard": h}
+		} else {
+			n, _ := strconv.Atoi(limit)
+			svc.Ulimits[name] = n
+		}
+	}
+	if cfg.Hardened {
+		svc.CapDrop = []string{"ALL"}
+		svc.CapAdd = hardenedCaps
+		svc.SecurityOpt = append(svc.SecurityOpt, "no-new-privileges")
+	}
+	return out
+}
+
+// CmdCompose implements `openhands compose [WORKSPACE]`: it prints a compose
+// file equivalent to the launch the same flags would perform, for teams that
+// check a docker-compose.yml into the repo instead of running the launcher
+// directly.
+func CmdCompose(args []string) int {
+	cfg := &Config{}
+	fs := flag.NewFlagSet("compose", flag.ExitOnError)
+	RegisterLaunchFlags(fs, cfg)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands compose [flags] [WORKSPACE]\n\nPrints a docker-compose.yml for the equivalent launch to stdout.\nWORKSPACE defaults to the current directory.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+	cfg.Workspace = fs.Arg(0)
+	if err := FinalizeConfig(cfg, fs); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if cfg.GPUs != "" {
+		warnf("-gpus has no direct compose equivalent; add a device reservation to the generated file by hand")
+	}
+	raw, err := yaml.Marshal(buildComposeFile(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: rendering compose file: %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(raw)
+	return 0
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// composeTestConfig pins every environment-dependent input so the rendered
+// file is identical across machines.
+func composeTestConfig() *Config {
+	return &Config{
+		Tag:       DefaultTag,
+		Port:      DefaultPort,
+		Bind:      "127.0.0.1",
+		Workspace: "/home/dev/project",
+		User:      "1000:1000",
+		Timezone:  "UTC",
+		stateDir:  "/home/dev/.openhands",
+		LLM:       LLMConfig{Model: "anthropic/claude-sonnet-4-20250514", APIKey: "sk-test-not-a-real-key"},
+	}
+}
+
+func TestBuildComposeFileGolden(t *testing.T) {
+	t.Setenv("LANG", "")
+	t.Setenv("LC_ALL", "")
+	savedEngine := currentEngine
+	currentEngine = engine{Kind: "docker", SocketPath: "/var/run/docker.sock"}
+	defer func() { currentEngine = savedEngine }()
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"default", func(cfg *Config) {}},
+		{"flags", func(cfg *Config) {
+			cfg.Name = "review-bot"
+			cfg.Port = 3100
+			cfg.Bind = "0.0.0.0"
+			cfg.ExtraMounts = []string{"/home/dev/data:/data:ro"}
+			cfg.env = map[string]string{"DEBUG": "1"}
+			cfg.ShmSize = "2g"
+			cfg.Memory = "4g"
+			cfg.CPUs = 2.5
+			cfg.Ulimits = []string{"nofile=65535:65535", "nproc=4096"}
+			cfg.Hardened = true
+			cfg.Network = "backend"
+		}},
+		{"host-network-task", func(cfg *Config) {
+			cfg.Network = "host"
+			cfg.NoDockerSock = true
+			cfg.Task = "fix the failing tests"
+			cfg.MaxIterations = 10
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := composeTestConfig()
+			tt.mutate(cfg)
+			raw, err := yaml.Marshal(buildComposeFile(cfg))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			golden := filepath.Join("testdata", "compose", tt.name+".yml")
+			if *updateGolden {
+				if err := os.WriteFile(golden, raw, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading golden file (regenerate with `go test -run TestBuildComposeFileGolden -update`): %v", err)
+			}
+			if string(raw) != string(want) {
+				t.Errorf("compose output differs from %s:\n got:\n%s\nwant:\n%s", golden, raw, want)
+			}
+		})
+	}
+}
+
+func TestComposeMasksAPIKey(t *testing.T) {
+	t.Setenv("LANG", "")
+	t.Setenv("LC_ALL", "")
+	cfg := composeTestConfig()
+	raw, err := yaml.Marshal(buildComposeFile(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(raw)
+	if !strings.Contains(out, "LLM_API_KEY=${LLM_API_KEY}") {
+		t.Error("compose output should reference the API key as ${LLM_API_KEY}")
+	}
+	if strings.Contains(out, "sk-test-not-a-real-key") {
+		t.Error("compose output leaks the literal API key")
+	}
+}
@@ -0,0 +1,859 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// Default images and tags mirror the docker-compose setup at the repository
+// root. The runtime image tag is derived from the app tag plus the
+// "-nikolaik" suffix used by published runtime images.
+const (
+	DefaultAppImage     = "docker.openhands.dev/openhands/openhands"
+	DefaultRuntimeImage = "docker.openhands.dev/openhands/runtime"
+	DefaultTag          = "0.62"
+	RuntimeTagSuffix    = "-nikolaik"
+	DefaultPort         = 3000
+
+	// WorkspaceMountPath is where the workspace is mounted inside the app
+	// container under the legacy mounting scheme; see useSandboxVolumes.
+	WorkspaceMountPath = "/opt/workspace_base"
+	// SandboxWorkspacePath is where SANDBOX_VOLUMES-aware releases mount the
+	// workspace inside the sandbox runtime.
+	SandboxWorkspacePath = "/workspace"
+	// StateMountPath is where the host's state directory is mounted inside
+	// the app container, so conversations and settings survive the
+	// container being removed.
+	StateMountPath = "/.openhands"
+)
+
+// LLMConfig holds the LLM settings forwarded to the app container.
+type LLMConfig struct {
+	Model  string
+	APIKey string
+	// BaseURL points at an OpenAI-compatible or Ollama endpoint, used for
+	// local or self-hosted models. Local servers must be addressed as
+	// host.docker.internal, not localhost, to be reachable from the
+	// container.
+	BaseURL string
+	// APIVersion selects the API version for providers that need one,
+	// notably Azure OpenAI.
+	APIVersion string
+}
+
+// Config collects everything the launcher needs to start an OpenHands
+// instance. It is populated from command line flags in main.
+type Config struct {
+	// Workspace is the host directory mounted into the sandbox.
+	Workspace string
+	// Tag selects the app image version, e.g. "0.62".
+	Tag string
+	// Port is the host port the UI is published on.
+	Port int
+	// PortRetry falls back to a free port when the requested one is busy,
+	// instead of failing; see resolvePort.
+	PortRetry bool
+	// Bind is the host address the UI port is published on. The loopback
+	// default keeps the instance - and the docker-socket-wielding agent
+	// behind it - off the LAN; 0.0.0.0 opts into exposure.
+	Bind string
+	// LLM holds model and credential settings passed to the server.
+	LLM LLMConfig
+	// RuntimeImageOverride replaces the derived sandbox runtime image when
+	// set (from config files).
+	RuntimeImageOverride string
+	// VerifyDigest resolves and prints the digest each tag-based image
+	// actually runs as; ExpectedDigest additionally refuses to start when
+	// the app image resolves to a different sha256.
+	VerifyDigest   bool
+	ExpectedDigest string
+	// ExtraMounts are additional host bind mounts in "src:dst[:opts]"
+	// form, added to the app container.
+	ExtraMounts []string
+	// DockerArgs are raw `docker run` options passed through to the app
+	// container, validated against the managed options first; see
+	// checkDockerArgs and applyDockerArgs.
+	DockerArgs []string
+	// LegacyMounts forces the pre-SANDBOX_VOLUMES workspace mounting scheme,
+	// for custom tags built from an old tree; see useSandboxVolumes.
+	LegacyMounts bool
+	// ReadOnly mounts the workspace read-only, end to end: the bind mode and
+	// the sandbox mount env both carry it, so the agent can analyze the code
+	// but not change it.
+	ReadOnly bool
+	// RestartOnCrash relaunches a crashed server up to this many times with
+	// backoff, keeping the name and port; see superviseContainer.
+	RestartOnCrash int
+	// Budget caps the per-task LLM spend in dollars; zero means no cap.
+	Budget float64
+	// SecretsFile is a dotenv file whose variables are injected only into
+	// the sandbox runtime, not the app container's own environment.
+	SecretsFile string
+	// Task, when non-empty, runs the container in headless mode working on
+	// this task instead of serving the UI.
+	Task string
+	// MaxIterations caps the agent's iterations in headless mode, passed
+	// through to the backend; zero means the server default.
+	MaxIterations int
+	// TaskTimeout stops the container when a headless task runs longer
+	// than this; zero means no limit.
+	TaskTimeout time.Duration
+	// ServerConfig is a full backend config.toml mounted into the app
+	// container, for options that have no env-var equivalent.
+	ServerConfig string
+	// SandboxEnv are variables exported into the agent's shell inside the
+	// sandbox runtime, as opposed to the app container's environment.
+	SandboxEnv map[string]string
+	// EnvVars are -env specs ("KEY=VALUE", or a bare "KEY" inheriting the
+	// shell's value) set on the app container itself, for the many server
+	// options (SANDBOX_*, LOG_ALL_EVENTS, ...) the launcher has no
+	// dedicated flag for. EnvFile reads more of them from a dotenv file.
+	EnvVars []string
+	EnvFile string
+	// ForwardEnvPrefixes extends the env var prefixes forwarded from the
+	// shell (default LLM_, SANDBOX_, AGENT_, SECURITY_); NoEnvForward
+	// turns the passthrough off entirely.
+	ForwardEnvPrefixes []string
+	NoEnvForward       bool
+	// env is the resolved set, built by ValidateFlags.
+	env map[string]string
+	// User overrides the uid:gid the sandbox runs as. Empty means the
+	// invoking user's UID, which is right except for workspaces owned by
+	// someone else (NFS, sudo).
+	User string
+	// Timezone overrides the TZ forwarded to the containers; empty means
+	// the host's $TZ (or system zone) is passed through.
+	Timezone string
+	// ShmSize is the /dev/shm size for the app container, e.g. "2g".
+	// Docker's 64MB default is too small for Chromium-based browsing.
+	ShmSize string
+	// shmSizeBytes is ShmSize parsed by ValidateFlags.
+	shmSizeBytes int64
+	// Ulimits raises resource limits inside the container, in docker's
+	// "name=soft[:hard]" form; builds and test suites regularly exhaust
+	// the default descriptor and process limits.
+	Ulimits []string
+	// ulimits is Ulimits parsed by ValidateFlags.
+	ulimits []*units.Ulimit
+	// SecurityOpts are passed through to docker's --security-opt, for
+	// sites that must run the agent under a custom seccomp or AppArmor
+	// profile.
+	SecurityOpts []string
+	// securityOpts is SecurityOpts with seccomp profile paths resolved to
+	// their JSON content, as the engine API expects; set by ValidateFlags.
+	securityOpts []string
+	// Hardened applies the restrictive container posture (drop all
+	// capabilities but the few the server needs, no-new-privileges) in one
+	// flag, for security teams that want an approved preset rather than a
+	// hand-rolled --security-opt/--cap-drop combination.
+	Hardened bool
+	// Scan runs a vulnerability scanner over the app and runtime images
+	// before first use of a tag; AcceptRisk lets a launch proceed despite
+	// critical findings.
+	Scan       bool
+	AcceptRisk bool
+	// EnableExperiments names the experimental behaviors to turn on; see
+	// knownExperiments. experiments is the validated set.
+	EnableExperiments []string
+	experiments       map[string]bool
+	// CopyURL copies the UI URL to the clipboard once the server is ready,
+	// for remote/tmux sessions where selecting it from logs is painful.
+	CopyURL bool
+	// Detach returns control once the server is ready, leaving the
+	// container running in the background (openhands stop shuts it down).
+	Detach bool
+	// DryRun prints the equivalent docker run command and exits instead of
+	// launching; ShowSecrets leaves credential values unmasked in it.
+	DryRun      bool
+	ShowSecrets bool
+	// ConfigFile overrides the global config location (~/.openhands/
+	// cli.toml); the repo config is still merged on top.
+	ConfigFile string
+	// Force overrides the safety check that refuses to default the
+	// workspace to / or the home directory.
+	Force bool
+	// CreateWorkspace makes a missing workspace directory instead of
+	// failing, for starting the agent on a fresh project.
+	CreateWorkspace bool
+	// NoInteractive suppresses the first-run setup wizard and any other
+	// prompting, for CI use.
+	NoInteractive bool
+	// Ephemeral runs the agent against a temporary copy of the workspace;
+	// the changes are reviewed at the end and applied, exported or
+	// discarded, so the original directory is never touched.
+	Ephemeral bool
+	// Pull is the image pull policy: "always", "missing" (default) or
+	// "never".
+	Pull string
+	// NoUpdateCheck disables the asynchronous startup check for newer
+	// releases ($OPENHANDS_NO_UPDATE_CHECK works too).
+	NoUpdateCheck bool
+	// SkipPreflight skips the daemon reachability probe, for exotic
+	// setups (socket proxies, delayed-start daemons) that fail it but
+	// work anyway.
+	SkipPreflight bool
+	// Browser overrides the command used to open the UI URL ($BROWSER is
+	// honored too).
+	Browser string
+	// OpenPath is a route appended to the UI URL for readiness polling
+	// and browser opening, e.g. /settings.
+	OpenPath string
+	// OpenVSCode also opens the runtime's embedded VS Code server once
+	// the app is up.
+	OpenVSCode bool
+	// LogFile overrides where this launch's log is written (default
+	// ~/.openhands/logs/cli-<timestamp>.log); NoLogFile disables it and
+	// LogRetention caps how many old logs are kept. logWriter is the open
+	// log, set by Run and teed with the container's output.
+	LogFile      string
+	NoLogFile    bool
+	LogRetention int
+	logWriter    io.Writer
+	// Verbose prints debug-level launcher messages, including the full
+	// (masked) docker command; Quiet suppresses everything below warnings,
+	// leaving the UI URL as the only stdout output.
+	Verbose bool
+	Quiet   bool
+	// NoProxyPassthrough disables forwarding the shell's HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY into the app container; proxyEnv is the
+	// forwarded set, built by ValidateFlags.
+	NoProxyPassthrough bool
+	proxyEnv           []string
+	// Network attaches the app container to a named docker network, e.g.
+	// one shared with a self-hosted LLM endpoint. "host" drops the port
+	// publish entirely and serves on the container's own port.
+	Network string
+	// Memory caps the app container's RAM in docker units ("4g", "512m");
+	// CPUs caps its CPU time as a fractional core count. memoryBytes and
+	// nanoCPUs are the parsed forms, set by ValidateFlags. Zero means
+	// unlimited.
+	Memory      string
+	memoryBytes int64
+	CPUs        float64
+	nanoCPUs    int64
+	// GPUs exposes host GPUs to the app container in docker's --gpus
+	// syntax ("all", a count, or device=ID,...); gpus is the parsed form,
+	// set by ValidateFlags. SANDBOX_ENABLE_GPU is forwarded alongside so
+	// the server grants the sandbox runtime the same access.
+	GPUs string
+	gpus *gpuRequest
+	// Platform forces the image platform ("linux/amd64" or "linux/arm64")
+	// for pulls and the created container, for users who want emulation
+	// knowingly; empty lets the engine pick the native one. platform is the
+	// parsed form, set by ValidateFlags.
+	Platform string
+	platform *ocispec.Platform
+	// DockerSocket overrides engine socket detection with an explicit host
+	// path, for setups the probes cannot see (custom Colima profiles,
+	// nonstandard rootless paths).
+	DockerSocket string
+	// NoDockerSock keeps the engine socket out of the app container and
+	// switches the server to its local runtime: the agent runs inside the
+	// app container itself instead of a sandbox container.
+	NoDockerSock bool
+	// StartupTimeout bounds the wait for the server to become ready. When
+	// it expires the container is stopped (and removed, unless
+	// KeepContainer) after its log tail is shown.
+	StartupTimeout time.Duration
+	// Context selects a docker CLI context, as `docker --context` does.
+	// When its endpoint is a remote machine, RemoteWorkspace names the
+	// workspace path on that machine (local paths mean nothing there).
+	// contextEndpoint and remoteHost are resolved by ValidateFlags;
+	// remoteHost is empty for local endpoints.
+	Context         string
+	RemoteWorkspace string
+	contextEndpoint string
+	remoteHost      string
+	// portExplicit records that -port was given on the command line, set by
+	// FinalizeConfig; resolvePort only substitutes an explicit port under
+	// -port-retry.
+	portExplicit bool
+	// StateDir overrides the host directory mounted as the server's state
+	// store (conversations, settings, trajectories); empty means
+	// ~/.openhands. stateDir is the resolved path, set by ValidateFlags and
+	// left empty under -ephemeral, which keeps all state inside the
+	// container.
+	StateDir string
+	stateDir string
+	// Name overrides the app container's name, so a long-lived instance can
+	// be addressed in scripts and by stop/logs/ps. Recreate replaces an
+	// existing container under that name; Resume starts a stopped one
+	// instead of creating a fresh container.
+	Name     string
+	Recreate bool
+	Resume   bool
+	// KeepContainer leaves the exited app container behind instead of
+	// auto-removing it, so its filesystem can be inspected after a crash.
+	KeepContainer bool
+	// InsecureEnvSecrets passes the LLM API key as a plain container
+	// environment variable, the pre-key-file behavior, for setups that
+	// depend on reading it from the app container's environment.
+	InsecureEnvSecrets bool
+	// llmKeyFile is the key file written for this launch; set by
+	// CreateContainer, removed by Run.
+	llmKeyFile string
+	// stages renders launch lifecycle progress; nil outside the launch
+	// path and in quiet mode.
+	stages *stageReporter
+	// sandboxSecrets holds the parsed secrets. Values must never be
+	// printed or logged.
+	sandboxSecrets map[string]string
+}
+
+// LoadSecrets parses SecretsFile, if set. It reports which variable names
+// (never values) will reach the sandbox.
+func (c *Config) LoadSecrets() error {
+	if c.SecretsFile == "" {
+		return nil
+	}
+	vars, err := parseEnvFile(c.SecretsFile)
+	if err != nil {
+		return fmt.Errorf("secrets file: %w", err)
+	}
+	c.sandboxSecrets = vars
+	if len(vars) > 0 {
+		infof("Forwarding %d secret(s) to the sandbox runtime: %s", len(vars), strings.Join(sortedKeys(vars), ", "))
+	}
+	return nil
+}
+
+// containerName returns the app container's name: the -name override when
+// given, the fixed default otherwise.
+func (c *Config) containerName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return ContainerName
+}
+
+// mountWorkspace is the host-side path of the workspace bind: the remote
+// path when launching through a remote docker context, the local workspace
+// otherwise.
+func (c *Config) mountWorkspace() string {
+	if c.RemoteWorkspace != "" {
+		return c.RemoteWorkspace
+	}
+	return c.Workspace
+}
+
+// AppImage returns the fully qualified app image reference. A -tag of the
+// form [@]sha256:... pins the image by digest instead of a mutable tag.
+func (c *Config) AppImage() string {
+	if d, ok := digestTag(c.Tag); ok {
+		return DefaultAppImage + "@" + d
+	}
+	return fmt.Sprintf("%s:%s", DefaultAppImage, c.Tag)
+}
+
+// RuntimeImage returns the sandbox runtime image matching the app tag, or
+// the configured override.
+func (c *Config) RuntimeImage() string {
+	if c.RuntimeImageOverride != "" {
+		return c.RuntimeImageOverride
+	}
+	return fmt.Sprintf("%s:%s%s", DefaultRuntimeImage, c.Tag, RuntimeTagSuffix)
+}
+
+// parseUserSpec validates a "uid" or "uid:gid" override and returns the
+// numeric parts (gid -1 when absent).
+func parseUserSpec(spec string) (uid, gid int, err error) {
+	uidStr, gidStr, hasGID := strings.Cut(spec, ":")
+	parse := func(s, what string) (int, error) {
+		n := 0
+		for _, r := range s {
+			if r < '0' || r > '9' {
+				return 0, fmt.Errorf("invalid -user %q: %s must be numeric", spec, what)
+			}
+			n = n*10 + int(r-'0')
+		}
+		if s == "" {
+			return 0, fmt.Errorf("invalid -user %q: %s is empty", spec, what)
+		}
+		return n, nil
+	}
+	if uid, err = parse(uidStr, "uid"); err != nil {
+		return 0, 0, err
+	}
+	gid = -1
+	if hasGID {
+		if gid, err = parse(gidStr, "gid"); err != nil {
+			return 0, 0, err
+		}
+	}
+	return uid, gid, nil
+}
+
+// resolveSecurityOpt prepares one --security-opt value for the engine API.
+// The docker CLI reads seccomp profile files client-side and sends their JSON
+// content; we do the same, so `--security-opt seccomp=profile.json` works the
+// way users expect. Other options (apparmor=..., no-new-privileges, seccomp
+// keywords like "unconfined") pass through untouched.
+func resolveSecurityOpt(opt string) (string, error) {
+	path, ok := strings.CutPrefix(opt, "seccomp=")
+	if !ok || path == "unconfined" || path == "builtin" {
+		return opt, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid -security-opt %q: %w", opt, err)
+	}
+	return "seccomp=" + string(raw), nil
+}
+
+// timezone returns the zone to forward: the -tz override, then $TZ, then
+// the system zone from /etc/timezone where available.
+func (c *Config) timezone() string {
+	if c.Timezone != "" {
+		return c.Timezone
+	}
+	if tz := os.Getenv("TZ"); tz != "" {
+		return tz
+	}
+	if raw, err := os.ReadFile("/etc/timezone"); err == nil {
+		return strings.TrimSpace(string(raw))
+	}
+	return ""
+}
+
+// sandboxUID returns the UID the sandbox should run as.
+func (c *Config) sandboxUID() int {
+	if c.User != "" {
+		uid, _, err := parseUserSpec(c.User)
+		if err == nil {
+			return uid
+		}
+	}
+	return os.Getuid()
+}
+
+// memoryFloorBytes is the memory limit below which a warning is printed;
+// the server plus one sandbox comfortably exceed smaller caps.
+const memoryFloorBytes = 2 << 30
+
+// parseMemoryLimit validates a -memory value in docker units.
+func parseMemoryLimit(spec string) (int64, error) {
+	size, err := units.RAMInBytes(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid -memory %q: %w", spec, err)
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("invalid -memory %q: the limit must be positive", spec)
+	}
+	return size, nil
+}
+
+// resolveStateDir returns the host directory to mount as the server's state
+// store, creating it when missing. State files hold conversation history and
+// credentials, so a fresh directory is made user-only; the CLI runs as the
+// invoking user, which matches the SANDBOX_USER_ID passed to the server.
+func resolveStateDir(override string) (string, error) {
+	if override == "" {
+		return StateDir()
+	}
+	path := override
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding ~ in -state-dir: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolving -state-dir: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0o700); err != nil {
+		return "", fmt.Errorf("creating state directory %s: %w", abs, err)
+	}
+	return abs, nil
+}
+
+// normalizeWorkspace turns whatever the user passed - ~/project, a relative
+// path, a symlink like /tmp on macOS - into the real absolute directory that
+// will be handed to the engine. Docker binds the resolved path, so errors
+// and labels should name it too. With create set, a missing directory is
+// made instead of reported.
+func normalizeWorkspace(path string, create bool) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding ~ in workspace path: %w", err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolving workspace path: %w", err)
+	}
+	if _, err := os.Stat(abs); os.IsNotExist(err) && create {
+		if err := os.MkdirAll(abs, 0o755); err != nil {
+			return "", fmt.Errorf("creating workspace %s: %w", abs, err)
+		}
+		infof("Created workspace directory %s", abs)
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("workspace %s does not exist (pass -create-workspace to make it)", abs)
+		}
+		return "", fmt.Errorf("workspace %s: %w", abs, err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("workspace %s: %w", resolved, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("workspace %s is not a directory", resolved)
+	}
+	return resolved, nil
+}
+
+// warnUnsharedWorkspace flags workspaces that Docker Desktop on macOS will
+// refuse to bind: only directories under its File Sharing list (by default
+// /Users, /Volumes, /private and /tmp) can be mounted, and the eventual
+// engine error does not say so.
+func warnUnsharedWorkspace(path string) {
+	if runtime.GOOS != "darwin" {
+		return
+	}
+	for _, prefix := range []string{"/Users/", "/Volumes/", "/private/", "/tmp/"} {
+		if strings.HasPrefix(path+"/", prefix) {
+			return
+		}
+	}
+	warnf("Warning: %s is outside Docker Desktop's default file sharing locations; add it under Settings > Resources > File Sharing if the mount fails.", path)
+}
+
+// uiHost is the hostname the user should open in the browser. Binding to
+// the wildcard address still means connecting via localhost.
+func (c *Config) uiHost() string {
+	if c.remoteHost != "" {
+		// The container runs on the context's machine; that is where the
+		// port is published.
+		return c.remoteHost
+	}
+	if c.Bind == "" || c.Bind == "0.0.0.0" || c.Bind == "::" {
+		return "localhost"
+	}
+	return c.Bind
+}
+
+// uiURL is the URL the UI is reachable at. Under host networking there is
+// no publish mapping; the server listens on its own port directly.
+func (c *Config) uiURL() string {
+	if c.Network == "host" {
+		host := "localhost"
+		if c.remoteHost != "" {
+			host = c.remoteHost
+		}
+		return fmt.Sprintf("http://%s:%d", host, DefaultPort)
+	}
+	return fmt.Sprintf("http://%s:%d", c.uiHost(), c.Port)
+}
+
+// openURL is uiURL plus the -open-path route: the page that is polled for
+// readiness and opened in the browser.
+func (c *Config) openURL() string {
+	return c.uiURL() + c.OpenPath
+}
+
+// rewriteLocalBaseURL maps a localhost LLM base URL to host.docker.internal,
+// because "localhost" inside the app container is the container itself and
+// the user's local model server would be unreachable. The second return
+// reports whether a rewrite happened, for logging.
+func rewriteLocalBaseURL(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw, false
+	}
+	host := u.Hostname()
+	if host != "localhost" && host != "127.0.0.1" && host != "::1" {
+		return raw, false
+	}
+	if port := u.Port(); port != "" {
+		u.Host = "host.docker.internal:" + port
+	} else {
+		u.Host = "host.docker.internal"
+	}
+	return u.String(), true
+}
+
+// guardDefaultWorkspace refuses to silently mount directories whose entire
+// contents the agent should almost never see: the filesystem root and the
+// home directory itself. Both remain usable as explicit arguments with
+// -force.
+func guardDefaultWorkspace(path string, force bool) error {
+	if force {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	if path == "/" || (home != "" && path == home) {
+		return fmt.Errorf("refusing to use %s as the default workspace; name a WORKSPACE explicitly or pass -force", path)
+	}
+	return nil
+}
+
+// dangerousWorkspace classifies workspace paths that should never be handed
+// to a coding agent wholesale, explicit argument or not. It returns the
+// reason, or "" for an unremarkable path. home is passed in so the
+// classification is testable across Linux and macOS layouts.
+func dangerousWorkspace(path, home string) string {
+	clean := filepath.Clean(path)
+	switch {
+	case clean == "/":
+		return "it is the filesystem root, handing the agent the entire machine"
+	case home != "" && clean == filepath.Clean(home):
+		return "it is your home directory, which holds credentials and personal files far beyond any one project"
+	case clean == "/etc" || strings.HasPrefix(clean, "/etc/"):
+		return "it is system configuration, including credential files"
+	case strings.Contains(clean, "/.ssh"):
+		return "it contains SSH keys (.ssh)"
+	}
+	return ""
+}
+
+// ValidateFlags checks the configuration for problems that are cheaper to
+// report up front than to let Docker fail on.
+func ValidateFlags(cfg *Config) error {
+	if cfg.Verbose && cfg.Quiet {
+		return fmt.Errorf("-verbose and -quiet are mutually exclusive")
+	}
+	setLogLevel(cfg.Verbose, cfg.Quiet)
+	if cfg.Workspace == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("no WORKSPACE given and the current directory is unknown: %w", err)
+		}
+		if err := guardDefaultWorkspace(cwd, cfg.Force); err != nil {
+			return err
+		}
+		infof("Mounting current directory: %s", cwd)
+		cfg.Workspace = cwd
+	}
+	ws, err := normalizeWorkspace(cfg.Workspace, cfg.CreateWorkspace)
+	if err != nil {
+		return err
+	}
+	warnUnsharedWorkspace(ws)
+	if !cfg.Force {
+		home, _ := os.UserHomeDir()
+		if reason := dangerousWorkspace(ws, home); reason != "" {
+			return fmt.Errorf("refusing to mount %s as the workspace: %s. The agent gets access to everything mounted; pick a project directory, or pass -force to override", ws, reason)
+		}
+	}
+	cfg.Workspace = ws
+	if cfg.Port <= 0 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid port %d", cfg.Port)
+	}
+	if cfg.Bind == "" {
+		cfg.Bind = "127.0.0.1"
+	}
+	if net.ParseIP(cfg.Bind) == nil {
+		return fmt.Errorf("invalid -bind %q: expected an IP address", cfg.Bind)
+	}
+	if cfg.User != "" {
+		if _, _, err := parseUserSpec(cfg.User); err != nil {
+			return err
+		}
+	}
+	if cfg.OpenPath != "" && !strings.HasPrefix(cfg.OpenPath, "/") {
+		cfg.OpenPath = "/" + cfg.OpenPath
+	}
+	if looksLikeDigest(cfg.Tag) {
+		if _, ok := digestTag(cfg.Tag); !ok {
+			return fmt.Errorf("invalid -tag digest %q: expected sha256: followed by 64 hex characters", cfg.Tag)
+		}
+		if cfg.RuntimeImageOverride == "" && !cfg.NoDockerSock {
+			return fmt.Errorf("a digest-pinned app image has no tag to derive the sandbox runtime image from; pin it too with sandbox_image in the config, or use -no-docker-sock")
+		}
+	}
+	if strings.Contains(cfg.RuntimeImageOverride, "@") {
+		if _, _, ok := splitDigestRef(cfg.RuntimeImageOverride); !ok {
+			return fmt.Errorf("invalid sandbox runtime image reference %q: the digest must be sha256: followed by 64 hex characters", cfg.RuntimeImageOverride)
+		}
+	}
+	if cfg.ExpectedDigest != "" {
+		cfg.ExpectedDigest = strings.TrimPrefix(cfg.ExpectedDigest, "@")
+		if !validDigest(cfg.ExpectedDigest) {
+			return fmt.Errorf("invalid -expected-digest %q: expected sha256: followed by 64 hex characters", cfg.ExpectedDigest)
+		}
+		// Comparing implies resolving.
+		cfg.VerifyDigest = true
+	}
+	if cfg.Ephemeral {
+		// Ephemeral runs leave no trace, state included.
+		cfg.stateDir = ""
+	} else {
+		dir, err := resolveStateDir(cfg.StateDir)
+		if err != nil {
+			return err
+		}
+		cfg.stateDir = dir
+	}
+	if cfg.Network == "host" {
+		// There is no publish mapping to remap or bind under host
+		// networking; the server listens on its own port directly.
+		if cfg.Port != DefaultPort {
+			return fmt.Errorf("-port cannot be combined with -network host: the server listens on port %d directly", DefaultPort)
+		}
+		if cfg.Bind != "127.0.0.1" {
+			return fmt.Errorf("-bind cannot be combined with -network host: there is no publish mapping to bind")
+		}
+	}
+	if cfg.Memory != "" {
+		size, err := parseMemoryLimit(cfg.Memory)
+		if err != nil {
+			return err
+		}
+		if size < memoryFloorBytes && cfg.memoryBytes == 0 {
+			warnf("Warning: -memory %s is below 2g; the server plus a sandbox usually need more and may be OOM-killed.", cfg.Memory)
+		}
+		cfg.memoryBytes = size
+	}
+	if cfg.CPUs < 0 {
+		return fmt.Errorf("invalid -cpus %g: the limit must be positive", cfg.CPUs)
+	}
+	if cfg.CPUs > 0 {
+		cfg.nanoCPUs = int64(cfg.CPUs * 1e9)
+	}
+	if cfg.GPUs != "" {
+		gpus, err := parseGPUs(cfg.GPUs)
+		if err != nil {
+			return err
+		}
+		cfg.gpus = gpus
+	}
+	platform, err := parsePlatform(cfg.Platform)
+	if err != nil {
+		return err
+	}
+	cfg.platform = platform
+	if cfg.DockerSocket != "" {
+		socketOverride = cfg.DockerSocket
+	}
+	if cfg.Context != "" {
+		endpoint, err := resolveDockerContext(cfg.Context)
+		if err != nil {
+			return err
+		}
+		cfg.contextEndpoint = endpoint
+		cfg.remoteHost = remoteEndpointHost(endpoint)
+		contextHost = endpoint
+	}
+	if cfg.remoteHost != "" && cfg.RemoteWorkspace == "" {
+		return fmt.Errorf("context %q runs containers on %s, where the local workspace path does not exist; pass -remote-workspace with a path valid on that machine", cfg.Context, cfg.remoteHost)
+	}
+	if cfg.StartupTimeout < 0 {
+		return fmt.Errorf("invalid -startup-timeout %s: the duration must be positive", cfg.StartupTimeout)
+	}
+	if cfg.StartupTimeout == 0 {
+		cfg.StartupTimeout = 3 * time.Minute
+	}
+	if cfg.NoDockerSock && cfg.RuntimeImageOverride != "" {
+		warnf("-no-docker-sock: the configured sandbox runtime image %s will not be used; the agent runs in the app container itself.", cfg.RuntimeImageOverride)
+	}
+	if cfg.Recreate && cfg.Resume {
+		return fmt.Errorf("-recreate and -resume are mutually exclusive")
+	}
+	if cfg.Resume && cfg.Name == "" {
+		return fmt.Errorf("-resume needs -name: only a named container can be resumed")
+	}
+	switch cfg.Pull {
+	case "", "always", "missing", "never":
+	default:
+		return fmt.Errorf("invalid -pull %q (expected always, missing or never)", cfg.Pull)
+	}
+	if cfg.ShmSize != "" {
+		size, err := units.RAMInBytes(cfg.ShmSize)
+		if err != nil {
+			return fmt.Errorf("invalid -shm-size %q: %w", cfg.ShmSize, err)
+		}
+		cfg.shmSizeBytes = size
+	}
+	cfg.ulimits = cfg.ulimits[:0]
+	for _, spec := range cfg.Ulimits {
+		ul, err := units.ParseUlimit(spec)
+		if err != nil {
+			return fmt.Errorf("invalid -ulimit %q: %w", spec, err)
+		}
+		cfg.ulimits = append(cfg.ulimits, ul)
+	}
+	home, _ := os.UserHomeDir()
+	cwd, _ := os.Getwd()
+	seenDest := map[string]bool{WorkspaceMountPath: true}
+	for i, spec := range cfg.ExtraMounts {
+		normalized, err := parseMountSpec(spec, home, cwd)
+		if err != nil {
+			return err
+		}
+		dst := mountDest(normalized)
+		if seenDest[dst] {
+			return fmt.Errorf("duplicate mount destination %s", dst)
+		}
+		seenDest[dst] = true
+		if _, err := os.Stat(mountSource(normalized)); err != nil {
+			return fmt.Errorf("mount source %s: %w", mountSource(normalized), err)
+		}
+		cfg.ExtraMounts[i] = normalized
+	}
+	// Under host networking the container shares the host's localhost, so
+	// a local LLM endpoint is reachable as-is and must not be rewritten.
+	if rewritten, ok := rewriteLocalBaseURL(cfg.LLM.BaseURL); ok && cfg.Network != "host" {
+		infof("Rewriting LLM base URL %s to %s so the container can reach it", cfg.LLM.BaseURL, rewritten)
+		cfg.LLM.BaseURL = rewritten
+	}
+	if !cfg.NoProxyPassthrough {
+		first := len(cfg.proxyEnv) == 0
+		cfg.proxyEnv = resolveProxyEnv(os.Getenv, cfg.Network != "host")
+		if first && len(cfg.proxyEnv) > 0 {
+			infof("Forwarding proxy settings from the shell: %s (disable with -no-proxy-passthrough)", strings.Join(envNames(cfg.proxyEnv), ", "))
+		}
+	}
+	var fileVars map[string]string
+	if cfg.EnvFile != "" {
+		fileVars, err = parseEnvFile(cfg.EnvFile)
+		if err != nil {
+			return fmt.Errorf("env file: %w", err)
+		}
+	}
+	cfg.env, err = resolveEnv(cfg.EnvVars, fileVars, os.Getenv, os.Stderr)
+	if err != nil {
+		return err
+	}
+	experiments, err := validateExperiments(cfg.EnableExperiments)
+	if err != nil {
+		return err
+	}
+	cfg.experiments = experiments
+	cfg.securityOpts = cfg.securityOpts[:0]
+	for _, opt := range cfg.SecurityOpts {
+		resolved, err := resolveSecurityOpt(opt)
+		if err != nil {
+			return err
+		}
+		cfg.securityOpts = append(cfg.securityOpts, resolved)
+	}
+	if cfg.ServerConfig != "" {
+		abs, err := filepath.Abs(cfg.ServerConfig)
+		if err != nil {
+			return fmt.Errorf("resolving server config path: %w", err)
+		}
+		if info, err := os.Stat(abs); err != nil {
+			return fmt.Errorf("server config %s: %w", abs, err)
+		} else if info.IsDir() {
+			return fmt.Errorf("server config %s is a directory, expected a config.toml", abs)
+		}
+		cfg.ServerConfig = abs
+	}
+	return nil
+}
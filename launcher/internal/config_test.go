@@ -0,0 +1,86 @@
+package internal
+
+import "testing"
+
+func TestRewriteLocalBaseURL(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      string
+		rewritten bool
+	}{
+		{"http://localhost:11434", "http://host.docker.internal:11434", true},
+		{"http://127.0.0.1:8000/v1", "http://host.docker.internal:8000/v1", true},
+		{"http://localhost/v1", "http://host.docker.internal/v1", true},
+		{"https://api.example.com/v1", "https://api.example.com/v1", false},
+		{"http://host.docker.internal:1234/v1", "http://host.docker.internal:1234/v1", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, rewritten := rewriteLocalBaseURL(tt.in)
+		if got != tt.want || rewritten != tt.rewritten {
+			t.Errorf("rewriteLocalBaseURL(%q) = %q, %v; want %q, %v", tt.in, got, rewritten, tt.want, tt.rewritten)
+		}
+	}
+}
+
+func TestParseMemoryLimit(t *testing.T) {
+	tests := []struct {
+		spec      string
+		wantBytes int64
+		wantErr   bool
+		wantWarn  bool
+	}{
+		{spec: "4g", wantBytes: 4 << 30},
+		{spec: "512m", wantBytes: 512 << 20, wantWarn: true},
+		{spec: "2g", wantBytes: 2 << 30},
+		{spec: "1536m", wantBytes: 1536 << 20, wantWarn: true},
+		{spec: "0", wantErr: true},
+		{spec: "-1g", wantErr: true},
+		{spec: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseMemoryLimit(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseMemoryLimit(%q): expected an error, got %d", tt.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseMemoryLimit(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.wantBytes {
+			t.Errorf("parseMemoryLimit(%q) = %d, want %d", tt.spec, got, tt.wantBytes)
+		}
+		if warn := got < memoryFloorBytes; warn != tt.wantWarn {
+			t.Errorf("parseMemoryLimit(%q): warning = %v, want %v", tt.spec, warn, tt.wantWarn)
+		}
+	}
+}
+
+func TestDangerousWorkspace(t *testing.T) {
+	tests := []struct {
+		path, home string
+		dangerous  bool
+	}{
+		{"/", "/home/alice", true},
+		{"/home/alice", "/home/alice", true},
+		{"/home/alice/", "/home/alice", true},
+		{"/Users/alice", "/Users/alice", true},
+		{"/etc", "/home/alice", true},
+		{"/etc/nginx", "/home/alice", true},
+		{"/home/alice/.ssh", "/home/alice", true},
+		{"/Users/alice/.ssh/backup", "/Users/alice", true},
+		{"/home/alice/project", "/home/alice", false},
+		{"/Users/alice/src/repo", "/Users/alice", false},
+		{"/tmp/scratch", "/home/alice", false},
+		{"/home/alice/etc-parser", "/home/alice", false},
+	}
+	for _, tt := range tests {
+		reason := dangerousWorkspace(tt.path, tt.home)
+		if (reason != "") != tt.dangerous {
+			t.Errorf("dangerousWorkspace(%q, %q) = %q, want dangerous=%v", tt.path, tt.home, reason, tt.dangerous)
+		}
+	}
+}
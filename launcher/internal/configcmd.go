@@ -0,0 +1,288 @@
+package internal
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CmdConfig implements `openhands config get|set|unset|list`: persisted CLI
+// settings without hand-editing TOML. Keys use the file's dotted paths
+// (llm.model, port, ...), values are validated like the matching flags, and
+// `list` masks secrets. `set KEY -stdin` reads the value from stdin so an
+// API key never lands in shell history. Unknown keys in the file are left
+// alone, so settings this version does not know about survive a set.
+func CmdConfig(args []string) int {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	fromStdin := fs.Bool("stdin", false, "with set: read the value from stdin instead of the command line")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands config list\n       openhands config get KEY\n       openhands config set KEY VALUE\n       openhands config set KEY -stdin\n       openhands config unset KEY\n\nKeys: %s\n", strings.Join(configKeyNames(), ", "))
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	verb, rest := args[0], args[1:]
+	// The -stdin flag may come after the key; parse flags wherever they are.
+	var words []string
+	for i := 0; i < len(rest); i++ {
+		if strings.HasPrefix(rest[i], "-") {
+			fs.Parse(rest[i:])
+			words = append(words, fs.Args()...)
+			break
+		}
+		words = append(words, rest[i])
+	}
+
+	if err := runConfigVerb(verb, words, *fromStdin); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func runConfigVerb(verb string, words []string, fromStdin bool) error {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+	doc, err := loadConfigDoc(path)
+	if err != nil {
+		return err
+	}
+	switch verb {
+	case "list":
+		for _, kv := range flattenConfig(doc, "") {
+			fmt.Printf("%s = %s\n", kv[0], maskConfigValue(kv[0], kv[1]))
+		}
+		return nil
+	case "get":
+		if len(words) != 1 {
+			return fmt.Errorf("usage: openhands config get KEY")
+		}
+		v, ok := lookupConfigKey(doc, words[0])
+		if !ok {
+			return fmt.Errorf("%s is not set", words[0])
+		}
+		fmt.Printf("%v\n", v)
+		return nil
+	case "set":
+		var key, value string
+		switch {
+		case fromStdin && len(words) == 1:
+			key = words[0]
+			raw, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && raw == "" {
+				return fmt.Errorf("reading value from stdin: %w", err)
+			}
+			value = strings.TrimRight(raw, "\r\n")
+		case !fromStdin && len(words) == 2:
+			key, value = words[0], words[1]
+		default:
+			return fmt.Errorf("usage: openhands config set KEY VALUE (or: set KEY -stdin)")
+		}
+		validate, ok := configKeys[key]
+		if !ok {
+			return fmt.Errorf("unknown key %q; known keys: %s", key, strings.Join(configKeyNames(), ", "))
+		}
+		parsed, err := validate(value)
+		if err != nil {
+			return err
+		}
+		setConfigKey(doc, key, parsed)
+		return writeConfigDoc(path, doc)
+	case "unset":
+		if len(words) != 1 {
+			return fmt.Errorf("usage: openhands config unset KEY")
+		}
+		if !unsetConfigKey(doc, words[0]) {
+			return fmt.Errorf("%s is not set", words[0])
+		}
+		return writeConfigDoc(path, doc)
+	default:
+		return fmt.Errorf("unknown config command %q (expected list, get, set or unset)", verb)
+	}
+}
+
+// configKeys maps the settable dotted keys to their validators, which apply
+// the same checks the matching flags do and return the value to store.
+var configKeys = map[string]func(value string) (interface{}, error){
+	"tag":           configString,
+	"sandbox_image": configString,
+	"llm.model":     configString,
+	"llm.api_key":   configString,
+	"llm.base_url":  configString,
+	"port": func(v string) (interface{}, error) {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 65535 {
+			return nil, fmt.Errorf("invalid port %q: expected a number between 1 and 65535", v)
+		}
+		return int64(n), nil
+	},
+	"memory": func(v string) (interface{}, error) {
+		if _, err := parseMemoryLimit(v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	},
+	"cpus": func(v string) (interface{}, error) {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f <= 0 {
+			return nil, fmt.Errorf("invalid cpus %q: expected a positive core count like 2.5", v)
+		}
+		return f, nil
+	},
+	"budget": func(v string) (interface{}, error) {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return nil, fmt.Errorf("invalid budget %q: expected a dollar amount", v)
+		}
+		return f, nil
+	},
+}
+
+func configString(v string) (interface{}, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, fmt.Errorf("the value is empty; use `config unset` to remove a key")
+	}
+	return v, nil
+}
+
+func configKeyNames() []string {
+	names := make([]string, 0, len(configKeys))
+	for k := range configKeys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadConfigDoc reads the config as a plain TOML document so keys this
+// version does not know about are preserved on rewrite. A missing file is an
+// empty document.
+func loadConfigDoc(path string) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return doc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// writeConfigDoc rewrites the config atomically: encode to a temp file in
+// the same directory, then rename over the original, so a crash cannot leave
+// a half-written file.
+func writeConfigDoc(path string, doc map[string]interface{}) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := toml.NewEncoder(tmp).Encode(doc); err != nil {
+		tmp.Close()
+		return fmt.Errorf("encoding %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lookupConfigKey resolves a dotted key against the document.
+func lookupConfigKey(doc map[string]interface{}, key string) (interface{}, bool) {
+	head, rest, nested := strings.Cut(key, ".")
+	v, ok := doc[head]
+	if !ok {
+		return nil, false
+	}
+	if !nested {
+		return v, true
+	}
+	sub, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	return lookupConfigKey(sub, rest)
+}
+
+// setConfigKey stores a dotted key, creating intermediate tables.
+func setConfigKey(doc map[string]interface{}, key string, value interface{}) {
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		doc[head] = value
+		return
+	}
+	sub, ok := doc[head].(map[string]interface{})
+	if !ok {
+		sub = map[string]interface{}{}
+		doc[head] = sub
+	}
+	setConfigKey(sub, rest, value)
+}
+
+// unsetConfigKey removes a dotted key, dropping tables it leaves empty.
+func unsetConfigKey(doc map[string]interface{}, key string) bool {
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		if _, ok := doc[head]; !ok {
+			return false
+		}
+		delete(doc, head)
+		return true
+	}
+	sub, ok := doc[head].(map[string]interface{})
+	if !ok || !unsetConfigKey(sub, rest) {
+		return false
+	}
+	if len(sub) == 0 {
+		delete(doc, head)
+	}
+	return true
+}
+
+// flattenConfig renders the document as sorted [key, value] pairs with
+// dotted paths, for `config list`.
+func flattenConfig(doc map[string]interface{}, prefix string) [][2]string {
+	var out [][2]string
+	for k, v := range doc {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if sub, ok := v.(map[string]interface{}); ok {
+			out = append(out, flattenConfig(sub, key)...)
+			continue
+		}
+		out = append(out, [2]string{key, fmt.Sprintf("%v", v)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i][0] < out[j][0] })
+	return out
+}
+
+// maskConfigValue hides credential-bearing values in list output, reusing
+// the env-var naming convention (llm.api_key looks like LLM_API_KEY).
+func maskConfigValue(key, value string) string {
+	envish := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if sensitiveEnvKey(envish) {
+		return maskSecret(value)
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigKeyRoundTrip(t *testing.T) {
+	doc := map[string]interface{}{}
+	setConfigKey(doc, "llm.model", "openai/gpt-4o")
+	setConfigKey(doc, "port", int64(3100))
+
+	if v, ok := lookupConfigKey(doc, "llm.model"); !ok || v != "openai/gpt-4o" {
+		t.Errorf("lookup llm.model = %v, %v", v, ok)
+	}
+	if !unsetConfigKey(doc, "llm.model") {
+		t.Error("unset llm.model failed")
+	}
+	if _, ok := doc["llm"]; ok {
+		t.Error("empty llm table should be dropped after unset")
+	}
+	if unsetConfigKey(doc, "llm.api_key") {
+		t.Error("unset of an absent key should report false")
+	}
+	if v, ok := lookupConfigKey(doc, "port"); !ok || v != int64(3100) {
+		t.Errorf("lookup port = %v, %v", v, ok)
+	}
+}
+
+func TestConfigValidators(t *testing.T) {
+	if _, err := configKeys["port"]("70000"); err == nil {
+		t.Error("out-of-range port accepted")
+	}
+	if _, err := configKeys["memory"]("lots"); err == nil {
+		t.Error("unparseable memory accepted")
+	}
+	if _, err := configKeys["cpus"]("-1"); err == nil {
+		t.Error("negative cpus accepted")
+	}
+	if v, err := configKeys["port"]("3100"); err != nil || v != int64(3100) {
+		t.Errorf("port 3100 = %v, %v", v, err)
+	}
+}
+
+func TestConfigListMasksSecrets(t *testing.T) {
+	doc := map[string]interface{}{
+		"llm": map[string]interface{}{"api_key": "sk-proj-verysecret12345", "model": "openai/gpt-4o"},
+	}
+	for _, kv := range flattenConfig(doc, "") {
+		rendered := maskConfigValue(kv[0], kv[1])
+		if strings.Contains(rendered, "sk-proj-verysecret") {
+			t.Errorf("list leaks the API key: %s = %s", kv[0], rendered)
+		}
+		if kv[0] == "llm.model" && rendered != "openai/gpt-4o" {
+			t.Errorf("non-secret value should not be masked: %s", rendered)
+		}
+	}
+}
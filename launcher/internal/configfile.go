@@ -0,0 +1,360 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configSchemaVersion is the current schema of the launcher's config file.
+// Older files are migrated in place by MigrateConfig.
+const configSchemaVersion = 1
+
+// RepoConfigPath is the per-repository config file, relative to the
+// workspace. Teams commit it so everyone launches the agent with the same
+// defaults.
+const RepoConfigPath = ".openhands/cli.toml"
+
+// FileConfig is the set of launcher settings that can come from config
+// files. The zero value of a field means "not set", so files can be merged
+// with later files overriding earlier ones.
+type FileConfig struct {
+	SchemaVersion int      `toml:"schema_version,omitempty"`
+	Tag           string   `toml:"tag,omitempty"`
+	Port          int      `toml:"port,omitempty"`
+	SandboxImage  string   `toml:"sandbox_image,omitempty"`
+	Mounts        []string `toml:"mounts,omitempty"`
+	Budget        float64  `toml:"budget,omitempty"`
+	Memory        string   `toml:"memory,omitempty"`
+	CPUs          float64  `toml:"cpus,omitempty"`
+	LLM           struct {
+		Model   string `toml:"model,omitempty"`
+		APIKey  string `toml:"api_key,omitempty"`
+		BaseURL string `toml:"base_url,omitempty"`
+	} `toml:"llm,omitempty"`
+	// Templates are named task presets for `openhands run @name`, so a
+	// team can standardize common workflows (review, add-tests, ...).
+	Templates map[string]TaskTemplate `toml:"templates,omitempty"`
+	// Experiments turns on named experimental behaviors for every launch,
+	// the config-file equivalent of -enable-experiment.
+	Experiments []string `toml:"experiments,omitempty"`
+}
+
+// TaskTemplate is one named preset: the initial task text plus optional
+// setting overrides that apply when the template is invoked.
+type TaskTemplate struct {
+	Task   string  `toml:"task"`
+	Model  string  `toml:"model,omitempty"`
+	Budget float64 `toml:"budget,omitempty"`
+	Tag    string  `toml:"tag,omitempty"`
+}
+
+// loadFileConfig parses one config file. A missing file yields an empty
+// config.
+func loadFileConfig(path string) (*FileConfig, error) {
+	var fc FileConfig
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &fc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.Unmarshal(raw, &fc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &fc, nil
+}
+
+// loadFileConfigAuto loads the config at path plus, when present, its
+// SOPS-encrypted sibling (cli.toml -> cli.enc.toml). The encrypted file is
+// decrypted transparently via the sops CLI and its values override the plain
+// file, so teams can commit shared secrets encrypted with age/GPG/KMS next
+// to harmless defaults.
+func loadFileConfigAuto(path string) (*FileConfig, error) {
+	fc, err := loadFileConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	encPath := strings.TrimSuffix(path, ".toml") + ".enc.toml"
+	if _, err := os.Stat(encPath); os.IsNotExist(err) {
+		return fc, nil
+	} else if err != nil {
+		return nil, err
+	}
+	enc, err := loadSOPSConfig(encPath)
+	if err != nil {
+		return nil, err
+	}
+	fc.mergeOver(enc)
+	return fc, nil
+}
+
+// loadSOPSConfig decrypts and parses a SOPS-encrypted TOML config.
+func loadSOPSConfig(path string) (*FileConfig, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return nil, fmt.Errorf("%s is SOPS-encrypted but the sops binary is not installed", path)
+	}
+	cmd := exec.Command("sops", "--input-type", "toml", "--output-type", "toml", "-d", path)
+	out, err := cmd.Output()
+	if err != nil {
+		var detail string
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			detail = ": " + strings.TrimSpace(string(ee.Stderr))
+		}
+		return nil, fmt.Errorf("decrypting %s%s: %w", path, detail, err)
+	}
+	var fc FileConfig
+	if err := toml.Unmarshal(out, &fc); err != nil {
+		return nil, fmt.Errorf("parsing decrypted %s: %w", path, err)
+	}
+	return &fc, nil
+}
+
+// mergeOver lays the non-zero fields of other on top of fc.
+func (fc *FileConfig) mergeOver(other *FileConfig) {
+	if other.Tag != "" {
+		fc.Tag = other.Tag
+	}
+	if other.Port != 0 {
+		fc.Port = other.Port
+	}
+	if other.SandboxImage != "" {
+		fc.SandboxImage = other.SandboxImage
+	}
+	if len(other.Mounts) > 0 {
+		fc.Mounts = other.Mounts
+	}
+	if other.Budget != 0 {
+		fc.Budget = other.Budget
+	}
+	if other.Memory != "" {
+		fc.Memory = other.Memory
+	}
+	if other.CPUs != 0 {
+		fc.CPUs = other.CPUs
+	}
+	if other.LLM.Model != "" {
+		fc.LLM.Model = other.LLM.Model
+	}
+	if other.LLM.APIKey != "" {
+		fc.LLM.APIKey = other.LLM.APIKey
+	}
+	if other.LLM.BaseURL != "" {
+		fc.LLM.BaseURL = other.LLM.BaseURL
+	}
+	for name, tpl := range other.Templates {
+		if fc.Templates == nil {
+			fc.Templates = make(map[string]TaskTemplate)
+		}
+		fc.Templates[name] = tpl
+	}
+	if len(other.Experiments) > 0 {
+		fc.Experiments = other.Experiments
+	}
+}
+
+// LoadMergedConfig returns the user's global config with the workspace's
+// .openhands/cli.toml (if any) merged on top. The returned path names the
+// repo config file when one was found, for reporting.
+func LoadMergedConfig(workspace string) (*FileConfig, string, error) {
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, "", err
+	}
+	return loadMergedConfigFrom(globalPath, workspace)
+}
+
+// LoadMergedConfigAt is LoadMergedConfig with an explicit global config path
+// (the -config flag). Unlike the default location, a named file that does
+// not exist is an error: the user asked for it specifically.
+func LoadMergedConfigAt(globalPath, workspace string) (*FileConfig, string, error) {
+	if _, err := os.Stat(globalPath); err != nil {
+		return nil, "", fmt.Errorf("config file %s: %w", globalPath, err)
+	}
+	return loadMergedConfigFrom(globalPath, workspace)
+}
+
+func loadMergedConfigFrom(globalPath, workspace string) (*FileConfig, string, error) {
+	merged, err := loadFileConfigAuto(globalPath)
+	if err != nil {
+		return nil, "", err
+	}
+	repoPath := filepath.Join(workspace, filepath.FromSlash(RepoConfigPath))
+	repo, err := loadFileConfigAuto(repoPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if isZeroConfig(repo) {
+		return merged, "", nil
+	}
+	merged.mergeOver(repo)
+	return merged, repoPath, nil
+}
+
+func isZeroConfig(fc *FileConfig) bool {
+	return fc.Tag == "" && fc.Port == 0 && fc.SandboxImage == "" &&
+		len(fc.Mounts) == 0 && fc.Budget == 0 && fc.Memory == "" && fc.CPUs == 0 &&
+		fc.LLM.Model == "" && fc.LLM.APIKey == "" &&
+		fc.LLM.BaseURL == "" && len(fc.Templates) == 0 && len(fc.Experiments) == 0
+}
+
+// LookupTemplate resolves a named template against the merged config for the
+// workspace. The error lists the available names, since a typo in a template
+// name is the common failure.
+func LookupTemplate(workspace, name string) (TaskTemplate, error) {
+	merged, _, err := LoadMergedConfig(workspace)
+	if err != nil {
+		return TaskTemplate{}, err
+	}
+	tpl, ok := merged.Templates[name]
+	if !ok {
+		if len(merged.Templates) == 0 {
+			return TaskTemplate{}, fmt.Errorf("no templates are defined; add a [templates.%s] section to %s or the global config", name, RepoConfigPath)
+		}
+		return TaskTemplate{}, fmt.Errorf("unknown template %q; defined templates: %s", name, strings.Join(sortedTemplateNames(merged.Templates), ", "))
+	}
+	if strings.TrimSpace(tpl.Task) == "" {
+		return TaskTemplate{}, fmt.Errorf("template %q has no task text", name)
+	}
+	return tpl, nil
+}
+
+func sortedTemplateNames(m map[string]TaskTemplate) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTemplate lays the template's setting overrides onto cfg, keeping flags
+// the user set explicitly in charge.
+func applyTemplate(cfg *Config, tpl TaskTemplate, flagWasSet func(name string) bool) {
+	if tpl.Model != "" && !flagWasSet("model") {
+		cfg.LLM.Model = tpl.Model
+	}
+	if tpl.Budget != 0 && !flagWasSet("budget") {
+		cfg.Budget = tpl.Budget
+	}
+	if tpl.Tag != "" && !flagWasSet("tag") {
+		cfg.Tag = tpl.Tag
+	}
+}
+
+// ApplyTo copies file settings into cfg for every flag the user did not set
+// explicitly, so the precedence stays flags > repo config > global config.
+func (fc *FileConfig) ApplyTo(cfg *Config, flagWasSet func(name string) bool) {
+	if fc.Tag != "" && !flagWasSet("tag") {
+		cfg.Tag = fc.Tag
+	}
+	if fc.Port != 0 && !flagWasSet("port") {
+		cfg.Port = fc.Port
+	}
+	if fc.LLM.Model != "" && !flagWasSet("model") {
+		cfg.LLM.Model = fc.LLM.Model
+	}
+	if fc.LLM.APIKey != "" && !flagWasSet("api-key") {
+		cfg.LLM.APIKey = fc.LLM.APIKey
+	}
+	if fc.LLM.BaseURL != "" && !flagWasSet("llm-base-url") {
+		cfg.LLM.BaseURL = fc.LLM.BaseURL
+	}
+	if fc.SandboxImage != "" {
+		cfg.RuntimeImageOverride = fc.SandboxImage
+	}
+	if len(fc.Mounts) > 0 {
+		cfg.ExtraMounts = append(cfg.ExtraMounts, fc.Mounts...)
+	}
+	if fc.Budget != 0 {
+		cfg.Budget = fc.Budget
+	}
+	if fc.Memory != "" && !flagWasSet("memory") {
+		cfg.Memory = fc.Memory
+	}
+	if fc.CPUs != 0 && !flagWasSet("cpus") {
+		cfg.CPUs = fc.CPUs
+	}
+	if len(fc.Experiments) > 0 {
+		cfg.EnableExperiments = append(cfg.EnableExperiments, fc.Experiments...)
+	}
+}
+
+// StateDir returns the launcher's state directory (~/.openhands), creating
+// it if necessary.
+func StateDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".openhands")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// GlobalConfigPath returns the path of the user's launcher config file.
+func GlobalConfigPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cli.toml"), nil
+}
+
+// MigrateConfig upgrades the config file to the current schema. It returns
+// true when the file was rewritten. A missing config file needs no
+// migration. The previous file is kept as cli.toml.bak.
+func MigrateConfig() (bool, error) {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return false, err
+	}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var doc map[string]interface{}
+	if err := toml.Unmarshal(raw, &doc); err != nil {
+		return false, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	have := 0
+	if v, ok := doc["schema_version"].(int64); ok {
+		have = int(v)
+	}
+	if have >= configSchemaVersion {
+		return false, nil
+	}
+	doc = migrateConfigDoc(doc, have)
+	if err := os.WriteFile(path+".bak", raw, 0o600); err != nil {
+		return false, fmt.Errorf("backing up %s: %w", path, err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	if err := toml.NewEncoder(f).Encode(doc); err != nil {
+		return false, fmt.Errorf("rewriting %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// migrateConfigDoc applies schema migrations step by step so each version
+// bump stays a small, reviewable change.
+func migrateConfigDoc(doc map[string]interface{}, from int) map[string]interface{} {
+	// v0 -> v1: files written before versioning gain an explicit
+	// schema_version. No key renames were needed for v1.
+	doc["schema_version"] = int64(configSchemaVersion)
+	return doc
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileConfigMissingFile(t *testing.T) {
+	fc, err := loadFileConfig(filepath.Join(t.TempDir(), "nope.toml"))
+	if err != nil {
+		t.Fatalf("loadFileConfig: %v", err)
+	}
+	if !isZeroConfig(fc) {
+		t.Fatalf("expected empty config, got %+v", fc)
+	}
+}
+
+func TestFileConfigMergeAndApply(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cli.toml")
+	const doc = `
+tag = "0.63"
+budget = 2.5
+mounts = ["/data:/data:ro"]
+
+[llm]
+model = "anthropic/claude-sonnet-4-20250514"
+`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := loadFileConfig(path)
+	if err != nil {
+		t.Fatalf("loadFileConfig: %v", err)
+	}
+
+	base := &FileConfig{Tag: "0.62", Port: 4000}
+	base.LLM.APIKey = "sk-global"
+	base.mergeOver(repo)
+	if base.Tag != "0.63" || base.Port != 4000 || base.LLM.APIKey != "sk-global" {
+		t.Fatalf("merge result unexpected: %+v", base)
+	}
+
+	cfg := &Config{Tag: DefaultTag, Port: DefaultPort}
+	cfg.LLM.Model = "from-flag"
+	base.ApplyTo(cfg, func(name string) bool { return name == "model" })
+	if cfg.Tag != "0.63" {
+		t.Errorf("Tag = %q, want 0.63", cfg.Tag)
+	}
+	if cfg.Port != 4000 {
+		t.Errorf("Port = %d, want 4000", cfg.Port)
+	}
+	if cfg.LLM.Model != "from-flag" {
+		t.Errorf("explicit -model flag should win, got %q", cfg.LLM.Model)
+	}
+	if cfg.Budget != 2.5 || len(cfg.ExtraMounts) != 1 {
+		t.Errorf("budget/mounts not applied: %+v", cfg)
+	}
+}
+
+func TestTemplatesMergeAndApply(t *testing.T) {
+	base := &FileConfig{Templates: map[string]TaskTemplate{
+		"review":    {Task: "global review"},
+		"add-tests": {Task: "add tests"},
+	}}
+	repo := &FileConfig{Templates: map[string]TaskTemplate{
+		"review": {Task: "repo review", Budget: 1.5, Model: "openai/gpt-5"},
+	}}
+	base.mergeOver(repo)
+	if base.Templates["review"].Task != "repo review" {
+		t.Errorf("repo template should override global: %+v", base.Templates["review"])
+	}
+	if base.Templates["add-tests"].Task != "add tests" {
+		t.Errorf("unrelated template lost in merge")
+	}
+
+	cfg := &Config{Tag: DefaultTag}
+	cfg.LLM.Model = "from-flag"
+	applyTemplate(cfg, base.Templates["review"], func(name string) bool { return name == "model" })
+	if cfg.LLM.Model != "from-flag" {
+		t.Errorf("explicit -model flag should beat the template, got %q", cfg.LLM.Model)
+	}
+	if cfg.Budget != 1.5 {
+		t.Errorf("Budget = %g, want 1.5", cfg.Budget)
+	}
+}
+
+func TestParseUserSpec(t *testing.T) {
+	uid, gid, err := parseUserSpec("1000:1000")
+	if err != nil || uid != 1000 || gid != 1000 {
+		t.Fatalf("parseUserSpec = (%d, %d, %v)", uid, gid, err)
+	}
+	uid, gid, err = parseUserSpec("0")
+	if err != nil || uid != 0 || gid != -1 {
+		t.Fatalf("parseUserSpec = (%d, %d, %v)", uid, gid, err)
+	}
+	for _, bad := range []string{"", "alice", "1000:", "1000:staff"} {
+		if _, _, err := parseUserSpec(bad); err == nil {
+			t.Errorf("parseUserSpec(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// CmdPause, CmdResume and CmdStopTask implement `openhands pause` /
+// `resume` / `stop-task`: the same agent control actions as the web UI's
+// buttons, so a runaway agent can be halted from the terminal that is
+// already streaming its output.
+func CmdPause(args []string) int    { return controlAgent("pause", "paused", args) }
+func CmdResume(args []string) int   { return controlAgent("resume", "running", args) }
+func CmdStopTask(args []string) int { return controlAgent("stop-task", "stopped", args) }
+
+// controlAgent posts one agent state change to the active conversation.
+func controlAgent(cmd, state string, args []string) int {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	conv := fs.String("conversation", "", "conversation ID (defaults to the most recent)")
+	fs.Parse(args)
+
+	client := newEventsClient(fmt.Sprintf("http://localhost:%d", *port))
+	convID := *conv
+	if convID == "" {
+		var err error
+		if convID, err = client.LatestConversation(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	if err := client.SetAgentState(convID, state); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Agent in conversation %s set to %s.\n", convID, state)
+	return 0
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// validDigest reports whether d is a well-formed sha256 image digest:
+// "sha256:" followed by exactly 64 hex characters.
+func validDigest(d string) bool {
+	rest, ok := strings.CutPrefix(d, "sha256:")
+	if !ok || len(rest) != 64 {
+		return false
+	}
+	for _, c := range rest {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// digestTag reports whether a -tag value pins the app image by digest
+// rather than naming a tag; both "sha256:..." and "@sha256:..." are
+// accepted. Only values that look like a digest attempt qualify - a plain
+// version tag returns false without complaint, mangled digests are caught
+// by ValidateFlags.
+func digestTag(tag string) (string, bool) {
+	d := strings.TrimPrefix(tag, "@")
+	if validDigest(d) {
+		return d, true
+	}
+	return "", false
+}
+
+// looksLikeDigest reports whether a -tag value is attempting a digest, so
+// a typo in one fails validation instead of being pulled as a literal tag.
+func looksLikeDigest(tag string) bool {
+	return strings.HasPrefix(tag, "@") || strings.HasPrefix(tag, "sha256:")
+}
+
+// splitDigestRef splits a digest-pinned reference "repo@sha256:..." into
+// its repository and digest. ok is false when ref has no digest or a
+// malformed one.
+func splitDigestRef(ref string) (repo, digest string, ok bool) {
+	repo, digest, found := strings.Cut(ref, "@")
+	if !found || repo == "" || strings.Contains(digest, "@") || !validDigest(digest) {
+		return "", "", false
+	}
+	return repo, digest, true
+}
+
+// verifyImageDigests resolves and prints the digest each tag-based launch
+// image actually runs as, and with -expected-digest refuses to start when
+// the app image resolved to something else. Digest-pinned references are
+// skipped: the daemon already enforced them byte for byte.
+func verifyImageDigests(ctx context.Context, cli *client.Client, cfg *Config) error {
+	refs := []string{cfg.AppImage()}
+	if !cfg.NoDockerSock {
+		refs = append(refs, cfg.RuntimeImage())
+	}
+	for i, ref := range refs {
+		if _, _, pinned := splitDigestRef(ref); pinned {
+			continue
+		}
+		resolved := imageDigest(ctx, cli, ref)
+		if resolved == "" {
+			// Locally built images carry no repo digest; nothing to
+			// verify against a registry.
+			infof("%s has no registry digest (locally built?)", ref)
+			continue
+		}
+		infof("%s resolves to %s", ref, resolved)
+		if i == 0 && cfg.ExpectedDigest != "" {
+			_, digest, _ := splitDigestRef(resolved)
+			if digest != cfg.ExpectedDigest {
+				return fmt.Errorf("app image %s resolves to %s, not the expected %s; refusing to start (drop -expected-digest to run it anyway)", ref, digest, cfg.ExpectedDigest)
+			}
+		}
+	}
+	return nil
+}
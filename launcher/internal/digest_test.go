@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+const goodDigest = "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+
+func TestValidDigest(t *testing.T) {
+	tests := []struct {
+		d    string
+		want bool
+	}{
+		{goodDigest, true},
+		{"", false},
+		{"sha256:", false},
+		{"sha256:abc", false},
+		{goodDigest + "00", false},
+		{"sha512:" + strings.Repeat("a", 64), false},
+		{"sha256:" + strings.Repeat("g", 64), false},
+		{"sha256:" + strings.Repeat("A", 64), false},
+		{strings.Repeat("a", 64), false},
+	}
+	for _, tt := range tests {
+		if got := validDigest(tt.d); got != tt.want {
+			t.Errorf("validDigest(%q) = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestDigestTag(t *testing.T) {
+	if d, ok := digestTag(goodDigest); !ok || d != goodDigest {
+		t.Errorf("digestTag(%q) = %q, %v", goodDigest, d, ok)
+	}
+	if d, ok := digestTag("@" + goodDigest); !ok || d != goodDigest {
+		t.Errorf("digestTag(@...) = %q, %v", d, ok)
+	}
+	for _, tag := range []string{"0.62", "latest", "sha256:abc", ""} {
+		if _, ok := digestTag(tag); ok {
+			t.Errorf("digestTag(%q) accepted a non-digest", tag)
+		}
+	}
+}
+
+func TestSplitDigestRef(t *testing.T) {
+	repo, digest, ok := splitDigestRef("docker.openhands.dev/openhands/runtime@" + goodDigest)
+	if !ok || repo != "docker.openhands.dev/openhands/runtime" || digest != goodDigest {
+		t.Errorf("splitDigestRef = %q, %q, %v", repo, digest, ok)
+	}
+	malformed := []string{
+		"",
+		"repo",
+		"repo:tag",
+		"@" + goodDigest,
+		"repo@",
+		"repo@sha256:abc",
+		"repo@sha512:" + strings.Repeat("a", 64),
+		"repo@" + goodDigest + "@" + goodDigest,
+	}
+	for _, ref := range malformed {
+		if _, _, ok := splitDigestRef(ref); ok {
+			t.Errorf("splitDigestRef(%q) accepted a malformed reference", ref)
+		}
+	}
+}
+
+func TestAppImageDigestPin(t *testing.T) {
+	cfg := &Config{Tag: "@" + goodDigest}
+	if got, want := cfg.AppImage(), DefaultAppImage+"@"+goodDigest; got != want {
+		t.Errorf("AppImage() = %q, want %q", got, want)
+	}
+	cfg.Tag = "0.62"
+	if got, want := cfg.AppImage(), DefaultAppImage+":0.62"; got != want {
+		t.Errorf("AppImage() = %q, want %q", got, want)
+	}
+}
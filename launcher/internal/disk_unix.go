@@ -0,0 +1,14 @@
+//go:build !windows
+
+package internal
+
+import "syscall"
+
+// freeDiskBytes reports the free space on the root filesystem.
+func freeDiskBytes() (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs("/", &st); err != nil {
+		return 0, false
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), true
+}
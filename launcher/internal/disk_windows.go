@@ -0,0 +1,33 @@
+//go:build windows
+
+package internal
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// freeDiskBytes reports the free space on the system drive.
+func freeDiskBytes() (uint64, bool) {
+	drive := os.Getenv("SystemDrive")
+	if drive == "" {
+		drive = "C:"
+	}
+	path, err := syscall.UTF16PtrFromString(drive + `\`)
+	if err != nil {
+		return 0, false
+	}
+	var avail, total, free uint64
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+	r, _, _ := proc.Call(
+		uintptr(unsafe.Pointer(path)),
+		uintptr(unsafe.Pointer(&avail)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)),
+	)
+	if r == 0 {
+		return 0, false
+	}
+	return avail, true
+}
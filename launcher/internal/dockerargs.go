@@ -0,0 +1,226 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+)
+
+// dockerArg is one normalized `docker run` option: "--env=K=V", "--env K=V"
+// and "-e K=V" all become {name: "env", value: "K=V"}.
+type dockerArg struct {
+	name  string
+	value string
+}
+
+// dockerArgAliases folds short option names onto their long forms, so the
+// collision and apply passes only reason about one spelling.
+var dockerArgAliases = map[string]string{
+	"e": "env",
+	"v": "volume",
+	"p": "publish",
+}
+
+// dockerValueFlags are the `docker run` options that consume the following
+// token as their value, covering everything buildDockerArgs emits plus the
+// passthrough options applyDockerArgs supports. Anything not listed is
+// treated as boolean.
+var dockerValueFlags = map[string]bool{
+	"env": true, "volume": true, "publish": true, "label": true, "name": true,
+	"add-host": true, "network": true, "user": true, "gpus": true,
+	"platform": true, "shm-size": true, "memory": true, "cpus": true,
+	"ulimit": true, "security-opt": true, "cap-add": true, "cap-drop": true,
+	"hostname": true, "dns": true, "device": true, "tmpfs": true,
+	"context": true,
+}
+
+// parseDockerArgs normalizes an argv fragment into dockerArgs. Tokens that
+// are not options (the image reference, a command) are skipped; that is fine
+// for both callers, which only compare and translate options.
+func parseDockerArgs(args []string) []dockerArg {
+	var out []dockerArg
+	for i := 0; i < len(args); i++ {
+		tok := args[i]
+		if !strings.HasPrefix(tok, "-") {
+			continue
+		}
+		name := strings.TrimLeft(tok, "-")
+		value := ""
+		if n, v, ok := strings.Cut(name, "="); ok {
+			name, value = n, v
+		}
+		if long, ok := dockerArgAliases[name]; ok {
+			name = long
+		}
+		if value == "" && dockerValueFlags[name] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		out = append(out, dockerArg{name: name, value: value})
+	}
+	return out
+}
+
+// publishHostPort extracts the host port from a -p spec ("3100:3000",
+// "127.0.0.1:3100:3000"); "" when the spec has no explicit host port.
+func publishHostPort(spec string) string {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-2]
+}
+
+// volumeDestination extracts the container path from a -v spec.
+func volumeDestination(spec string) string {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return ""
+	}
+	// src:dst or src:dst:opts; windows drive letters never reach this point
+	// because extras are given in docker syntax already.
+	return parts[1]
+}
+
+// dockerArgCollisions compares user-supplied docker options against the
+// argv the launcher builds and names every collision: a host port published
+// twice, two mounts on one destination, a second --name, or an environment
+// key the launcher already sets. The caller decides whether the collisions
+// are fatal (-force downgrades them to warnings).
+func dockerArgCollisions(managed, extra []string) []string {
+	ports := map[string]bool{}
+	dests := map[string]bool{}
+	envKeys := map[string]bool{}
+	hasName := false
+	for _, arg := range parseDockerArgs(managed) {
+		switch arg.name {
+		case "publish":
+			if p := publishHostPort(arg.value); p != "" {
+				ports[p] = true
+			}
+		case "volume":
+			if d := volumeDestination(arg.value); d != "" {
+				dests[d] = true
+			}
+		case "env":
+			if k, _, ok := strings.Cut(arg.value, "="); ok {
+				envKeys[k] = true
+			}
+		case "name":
+			hasName = true
+		}
+	}
+	var collisions []string
+	for _, arg := range parseDockerArgs(extra) {
+		switch arg.name {
+		case "publish":
+			if p := publishHostPort(arg.value); p != "" && ports[p] {
+				collisions = append(collisions, fmt.Sprintf("-p %s: host port %s is already published by the launcher (use -port to change it)", arg.value, p))
+			}
+		case "volume":
+			if d := volumeDestination(arg.value); d != "" && dests[d] {
+				collisions = append(collisions, fmt.Sprintf("-v %s: %s is already mounted by the launcher", arg.value, d))
+			}
+		case "env":
+			if k, _, ok := strings.Cut(arg.value, "="); ok && envKeys[k] {
+				collisions = append(collisions, fmt.Sprintf("-e %s: the launcher already sets %s", arg.value, k))
+			}
+		case "name":
+			if hasName {
+				collisions = append(collisions, "--name: the container name is already set; use the launcher's -name flag instead")
+			}
+		}
+	}
+	return collisions
+}
+
+// checkDockerArgs validates -docker-arg options against the managed argv.
+// Collisions are fatal unless -force downgrades them to warnings.
+func checkDockerArgs(cfg *Config) error {
+	if len(cfg.DockerArgs) == 0 {
+		return nil
+	}
+	saved := cfg.DockerArgs
+	cfg.DockerArgs = nil
+	managed := buildDockerArgs(cfg, false)
+	cfg.DockerArgs = saved
+	collisions := dockerArgCollisions(managed, cfg.DockerArgs)
+	if len(collisions) == 0 {
+		return nil
+	}
+	if cfg.Force {
+		for _, c := range collisions {
+			warnf("-docker-arg collides with a managed option: %s", c)
+		}
+		return nil
+	}
+	return fmt.Errorf("-docker-arg collides with options the launcher manages:\n  %s\nresolve the conflict or pass -force to proceed anyway", strings.Join(collisions, "\n  "))
+}
+
+// applyDockerArgs translates -docker-arg options onto the API request
+// CreateContainer builds. The launcher drives the engine API rather than the
+// docker binary, so only options with a direct API mapping are accepted;
+// anything else fails with the list of supported ones rather than being
+// silently dropped.
+func applyDockerArgs(cfg *Config, conf *container.Config, hostConf *container.HostConfig) error {
+	for _, arg := range parseDockerArgs(cfg.DockerArgs) {
+		switch arg.name {
+		case "env":
+			conf.Env = append(conf.Env, arg.value)
+		case "volume":
+			hostConf.Binds = append(hostConf.Binds, arg.value)
+		case "label":
+			if k, v, ok := strings.Cut(arg.value, "="); ok {
+				conf.Labels[k] = v
+			}
+		case "publish":
+			mappings, err := nat.ParsePortSpec(arg.value)
+			if err != nil {
+				return fmt.Errorf("-docker-arg -p %s: %w", arg.value, err)
+			}
+			if hostConf.PortBindings == nil {
+				hostConf.PortBindings = nat.PortMap{}
+			}
+			if conf.ExposedPorts == nil {
+				conf.ExposedPorts = nat.PortSet{}
+			}
+			for _, m := range mappings {
+				conf.ExposedPorts[m.Port] = struct{}{}
+				hostConf.PortBindings[m.Port] = append(hostConf.PortBindings[m.Port], m.Binding)
+			}
+		case "cap-add":
+			hostConf.CapAdd = append(hostConf.CapAdd, arg.value)
+		case "cap-drop":
+			hostConf.CapDrop = append(hostConf.CapDrop, arg.value)
+		case "privileged":
+			hostConf.Privileged = true
+		case "hostname":
+			conf.Hostname = arg.value
+		case "dns":
+			hostConf.DNS = append(hostConf.DNS, arg.value)
+		case "device":
+			mapping := container.DeviceMapping{CgroupPermissions: "rwm"}
+			parts := strings.SplitN(arg.value, ":", 3)
+			mapping.PathOnHost = parts[0]
+			mapping.PathInContainer = parts[0]
+			if len(parts) > 1 {
+				mapping.PathInContainer = parts[1]
+			}
+			if len(parts) > 2 {
+				mapping.CgroupPermissions = parts[2]
+			}
+			hostConf.Devices = append(hostConf.Devices, mapping)
+		case "tmpfs":
+			if hostConf.Tmpfs == nil {
+				hostConf.Tmpfs = map[string]string{}
+			}
+			path, opts, _ := strings.Cut(arg.value, ":")
+			hostConf.Tmpfs[path] = opts
+		default:
+			return fmt.Errorf("-docker-arg --%s is not supported by the launcher's API-based launch; supported options: -e, -v, -p, --label, --cap-add, --cap-drop, --privileged, --hostname, --dns, --device, --tmpfs", arg.name)
+		}
+	}
+	return nil
+}
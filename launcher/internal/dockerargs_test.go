@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDockerArgs(t *testing.T) {
+	got := parseDockerArgs([]string{"-e", "FOO=1", "--env=BAR=2", "--privileged", "-p", "8080:3000", "image:tag"})
+	want := []dockerArg{
+		{"env", "FOO=1"},
+		{"env", "BAR=2"},
+		{"privileged", ""},
+		{"publish", "8080:3000"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parsed %d args, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("arg %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDockerArgCollisions(t *testing.T) {
+	managed := []string{
+		"docker", "run", "-it", "--name", "openhands-app",
+		"-e", "LLM_MODEL=foo",
+		"-v", "/home/dev/project:/opt/workspace_base",
+		"-p", "127.0.0.1:3000:3000",
+		"image:tag",
+	}
+	tests := []struct {
+		name  string
+		extra []string
+		want  string // substring of the single expected collision, "" for none
+	}{
+		{"clean", []string{"--tmpfs", "/scratch", "-e", "DEBUG=1"}, ""},
+		{"duplicate host port", []string{"-p", "3000:8080"}, "host port 3000"},
+		{"different host port ok", []string{"-p", "8080:8080"}, ""},
+		{"duplicate volume destination", []string{"-v", "/tmp/x:/opt/workspace_base"}, "/opt/workspace_base"},
+		{"different destination ok", []string{"-v", "/tmp/x:/data"}, ""},
+		{"second name", []string{"--name", "mine"}, "--name"},
+		{"conflicting env key", []string{"-e", "LLM_MODEL=bar"}, "LLM_MODEL"},
+		{"fresh env key ok", []string{"--env=EXTRA=1"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dockerArgCollisions(managed, tt.extra)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("unexpected collisions: %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("collisions = %v, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDockerArgsForce(t *testing.T) {
+	cfg := &Config{
+		Workspace:  "/home/dev/project",
+		Tag:        "0.62",
+		Port:       3000,
+		Bind:       "127.0.0.1",
+		DockerArgs: []string{"--name", "mine"},
+	}
+	if err := checkDockerArgs(cfg); err == nil {
+		t.Error("collision should be fatal without -force")
+	}
+	cfg.Force = true
+	if err := checkDockerArgs(cfg); err != nil {
+		t.Errorf("-force should downgrade collisions to warnings: %v", err)
+	}
+}
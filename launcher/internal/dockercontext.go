@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// contextHost is the engine endpoint of the selected docker context (the
+// -context flag), consulted by NewDockerClient. Empty means no context was
+// selected.
+var contextHost string
+
+// resolveDockerContext looks up a docker CLI context by name and returns its
+// engine endpoint. Contexts live as meta.json files under the docker config
+// directory, keyed by the SHA-256 of the context name - the same layout
+// `docker context` itself reads and writes.
+func resolveDockerContext(name string) (string, error) {
+	if name == "" || name == "default" {
+		return "", nil
+	}
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locating home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	sum := sha256.Sum256([]byte(name))
+	metaPath := filepath.Join(dir, "contexts", "meta", hex.EncodeToString(sum[:]), "meta.json")
+	raw, err := os.ReadFile(metaPath)
+	if err != nil {
+		return "", fmt.Errorf("docker context %q not found (run `docker context ls`): %w", name, err)
+	}
+	var meta struct {
+		Name      string `json:"Name"`
+		Endpoints map[string]struct {
+			Host string `json:"Host"`
+		} `json:"Endpoints"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return "", fmt.Errorf("parsing context %q: %w", name, err)
+	}
+	ep, ok := meta.Endpoints["docker"]
+	if !ok || ep.Host == "" {
+		return "", fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+	if strings.HasPrefix(ep.Host, "ssh://") {
+		return "", fmt.Errorf("docker context %q uses an ssh endpoint, which this client cannot speak; expose the remote daemon over tcp (with TLS) instead", name)
+	}
+	return ep.Host, nil
+}
+
+// remoteEndpointHost returns the hostname of a non-local engine endpoint, or
+// "" for unix sockets and anything else that is this machine.
+func remoteEndpointHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	if u.Scheme == "tcp" {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// applyContextFlag resolves a -context flag on a management subcommand
+// (ps/stop/logs) so the whole lifecycle can target the same remote engine a
+// launch used.
+func applyContextFlag(name string) error {
+	host, err := resolveDockerContext(name)
+	if err != nil {
+		return err
+	}
+	contextHost = host
+	return nil
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeContextMeta(t *testing.T, dir, name, host string) {
+	t.Helper()
+	sum := sha256.Sum256([]byte(name))
+	metaDir := filepath.Join(dir, "contexts", "meta", hex.EncodeToString(sum[:]))
+	if err := os.MkdirAll(metaDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	meta := `{"Name":"` + name + `","Endpoints":{"docker":{"Host":"` + host + `"}}}`
+	if err := os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveDockerContext(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	writeContextMeta(t, dir, "build-host", "tcp://build.internal:2376")
+	writeContextMeta(t, dir, "over-ssh", "ssh://me@build.internal")
+
+	host, err := resolveDockerContext("build-host")
+	if err != nil || host != "tcp://build.internal:2376" {
+		t.Errorf("resolveDockerContext(build-host) = %q, %v", host, err)
+	}
+	if host, err := resolveDockerContext(""); err != nil || host != "" {
+		t.Errorf("empty context name should be a no-op, got %q, %v", host, err)
+	}
+	if host, err := resolveDockerContext("default"); err != nil || host != "" {
+		t.Errorf("default context should be a no-op, got %q, %v", host, err)
+	}
+	if _, err := resolveDockerContext("no-such"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing context error = %v", err)
+	}
+	if _, err := resolveDockerContext("over-ssh"); err == nil || !strings.Contains(err.Error(), "ssh") {
+		t.Errorf("ssh context error = %v", err)
+	}
+}
+
+func TestRemoteEndpointHost(t *testing.T) {
+	tests := []struct {
+		endpoint, want string
+	}{
+		{"tcp://build.internal:2376", "build.internal"},
+		{"tcp://10.0.0.5:2375", "10.0.0.5"},
+		{"unix:///var/run/docker.sock", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := remoteEndpointHost(tt.endpoint); got != tt.want {
+			t.Errorf("remoteEndpointHost(%q) = %q, want %q", tt.endpoint, got, tt.want)
+		}
+	}
+}
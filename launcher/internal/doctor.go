@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// checkResult is the outcome of one doctor check. Blocking results make the
+// command exit non-zero; informational ones only print.
+type checkResult struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Blocking bool   `json:"blocking"`
+	Detail   string `json:"detail"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// CmdDoctor implements `openhands doctor`: it diagnoses the local
+// environment and prints pass/fail per check with a remediation hint,
+// because "it hangs" is almost always the daemon, the socket, the port or a
+// missing image. -json emits the results as JSON for bug reports.
+func CmdDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print results as JSON, for attaching to bug reports")
+	port := fs.Int("port", DefaultPort, "UI port to check")
+	tag := fs.String("tag", DefaultTag, "image tag to check")
+	fs.Parse(args)
+
+	cfg := &Config{Tag: *tag, Port: *port}
+	results := runDoctorChecks(cfg)
+	if *asJSON {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(out))
+	} else {
+		for _, r := range results {
+			mark := "ok  "
+			if !r.OK {
+				mark = "FAIL"
+				if !r.Blocking {
+					mark = "warn"
+				}
+			}
+			fmt.Printf("%s  %-18s %s\n", mark, r.Name, r.Detail)
+			if !r.OK && r.Hint != "" {
+				fmt.Printf("      hint: %s\n", r.Hint)
+			}
+		}
+	}
+	return doctorExitCode(results)
+}
+
+// doctorExitCode is non-zero when any blocking check failed.
+func doctorExitCode(results []checkResult) int {
+	for _, r := range results {
+		if !r.OK && r.Blocking {
+			return 1
+		}
+	}
+	return 0
+}
+
+// runDoctorChecks executes every check in order. Later checks still run when
+// earlier ones fail, so one report shows the full picture.
+func runDoctorChecks(cfg *Config) []checkResult {
+	results := []checkResult{checkEngineSocket(), checkDaemon()}
+	// Image checks only make sense with a reachable daemon.
+	if results[1].OK {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		cli, err := NewDockerClient()
+		if err == nil {
+			defer cli.Close()
+			results = append(results,
+				checkImage(ctx, cli, cfg.AppImage()),
+				checkImage(ctx, cli, cfg.RuntimeImage()),
+				checkInstanceMode(ctx, cli),
+			)
+		}
+	}
+	results = append(results, checkPortFree(cfg.Port), checkDiskSpace(), checkArch(), checkLogs())
+	return results
+}
+
+// checkLogs points at the newest launch log, the file to attach to a bug
+// report.
+func checkLogs() checkResult {
+	if path := latestLogPath(); path != "" {
+		return checkResult{Name: "launch log", OK: true, Detail: "newest: " + path}
+	}
+	return checkResult{Name: "launch log", OK: true, Detail: "no launch logs yet"}
+}
+
+// checkEngineSocket reports which engine socket the launcher would use.
+func checkEngineSocket() checkResult {
+	host, eng := detectEngine(socketOverride, os.Getenv("DOCKER_HOST"), os.Getenv("XDG_RUNTIME_DIR"), fileExists)
+	detail := describeEngine(eng)
+	if host == "" && os.Getenv("DOCKER_HOST") == "" && !fileExists(eng.SocketPath) {
+		return checkResult{
+			Name: "engine socket", Blocking: true, Detail: detail,
+			Hint: "no container engine socket found; install Docker or Podman, or set DOCKER_HOST",
+		}
+	}
+	return checkResult{Name: "engine socket", OK: true, Detail: detail}
+}
+
+// checkDaemon probes daemon reachability, classifying a permission error on
+// the socket separately since it has a different fix.
+func checkDaemon() checkResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cli, err := NewDockerClient()
+	if err == nil {
+		defer cli.Close()
+		_, err = cli.Ping(ctx)
+	}
+	if err == nil {
+		return checkResult{Name: "daemon", OK: true, Detail: "daemon is responding"}
+	}
+	r := checkResult{Name: "daemon", Blocking: true, Detail: err.Error()}
+	if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EACCES) {
+		r.Hint = "the socket denied access; add yourself to the docker group (`usermod -aG docker $USER`) and re-login"
+	} else {
+		r.Hint = engineHint(currentEngine)
+	}
+	return r
+}
+
+// checkInstanceMode reports, per running instance, whether the agent runs in
+// sandbox containers (engine socket mounted) or inside the app container
+// itself (-no-docker-sock). Informational, but it settles "why is there no
+// runtime container" at a glance.
+func checkInstanceMode(ctx context.Context, cli *client.Client) checkResult {
+	instances, err := listInstances(ctx, cli)
+	if err != nil || len(instances) == 0 {
+		return checkResult{Name: "instances", OK: true, Detail: "no running instances"}
+	}
+	var parts []string
+	for _, c := range instances {
+		mode := "local runtime (no docker socket)"
+		if insp, err := cli.ContainerInspect(ctx, c.ID); err == nil {
+			for _, bind := range insp.HostConfig.Binds {
+				if strings.HasSuffix(bind, ":/var/run/docker.sock") {
+					mode = "sandbox containers (docker socket mounted)"
+					break
+				}
+			}
+		}
+		parts = append(parts, containerName(c)+": "+mode)
+	}
+	return checkResult{Name: "instances", OK: true, Detail: strings.Join(parts, "; ")}
+}
+
+// checkImage reports whether an image is already local. A missing image is
+// not blocking; it only means a large pull on first launch.
+func checkImage(ctx context.Context, cli *client.Client, ref string) checkResult {
+	if _, _, err := cli.ImageInspectWithRaw(ctx, ref); err != nil {
+		return checkResult{
+			Name: "image", Detail: ref + " is not present locally",
+			Hint: "the first launch will pull it; run `docker pull " + ref + "` to do so now",
+		}
+	}
+	return checkResult{Name: "image", OK: true, Detail: ref + " is present"}
+}
+
+// checkPortFree verifies the UI port can be bound.
+func checkPortFree(port int) checkResult {
+	if !portFree("127.0.0.1", port) {
+		return checkResult{
+			Name: "port", Blocking: true, Detail: fmt.Sprintf("port %d is already in use", port),
+			Hint: "stop whatever is listening there or launch with a different -port",
+		}
+	}
+	return checkResult{Name: "port", OK: true, Detail: fmt.Sprintf("port %d is free", port)}
+}
+
+// doctorMinDiskBytes is the free space below which the disk check warns; the
+// two images plus sandbox layers comfortably exceed it.
+const doctorMinDiskBytes = 10 << 30
+
+// checkDiskSpace warns when the filesystem holding the engine's storage is
+// nearly full. The root filesystem (system drive on Windows) stands in for
+// the storage location, which is right for default installations.
+func checkDiskSpace() checkResult {
+	free, ok := freeDiskBytes()
+	if !ok {
+		return checkResult{Name: "disk", OK: true, Detail: "free space unknown"}
+	}
+	detail := fmt.Sprintf("%.1f GB free", float64(free)/(1<<30))
+	if free < doctorMinDiskBytes {
+		return checkResult{
+			Name: "disk", Detail: detail,
+			Hint: "images and sandbox layers need several GB; run `docker system prune` or free up space",
+		}
+	}
+	return checkResult{Name: "disk", OK: true, Detail: detail}
+}
+
+// checkArch flags architectures where older tags were not published
+// multi-arch and run under slow emulation.
+func checkArch() checkResult {
+	detail := runtime.GOOS + "/" + runtime.GOARCH
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		return checkResult{
+			Name: "platform", Detail: detail,
+			Hint: "images are published for amd64 and arm64 only; other architectures need emulation",
+		}
+	}
+	return checkResult{Name: "platform", OK: true, Detail: detail}
+}
@@ -0,0 +1,34 @@
+package internal
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDoctorExitCode(t *testing.T) {
+	ok := []checkResult{{Name: "a", OK: true}, {Name: "b", OK: false, Blocking: false}}
+	if got := doctorExitCode(ok); got != 0 {
+		t.Errorf("non-blocking failure should exit 0, got %d", got)
+	}
+	bad := append(ok, checkResult{Name: "c", OK: false, Blocking: true})
+	if got := doctorExitCode(bad); got != 1 {
+		t.Errorf("blocking failure should exit 1, got %d", got)
+	}
+}
+
+func TestCheckPortFree(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	if r := checkPortFree(port); r.OK {
+		t.Errorf("occupied port %d reported free", port)
+	}
+	ln.Close()
+	if r := checkPortFree(port); !r.OK {
+		t.Errorf("free port %d reported occupied: %s", port, r.Detail)
+	}
+}
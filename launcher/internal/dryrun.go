@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// secretMask replaces secret values in dry-run output.
+const secretMask = "********"
+
+// buildDockerArgs renders the `docker run` invocation equivalent to what
+// CreateContainer asks of the engine API, for -dry-run output. It exists so
+// a failing setup can be reproduced in a plain shell or pasted into a bug
+// report; keep it in sync with CreateContainer when adding options there.
+func buildDockerArgs(cfg *Config, showSecrets bool) []string {
+	args := []string{"docker"}
+	if cfg.Context != "" {
+		args = append(args, "--context", cfg.Context)
+	}
+	args = append(args, "run", "-it", "--name", cfg.containerName())
+	if !cfg.KeepContainer {
+		args = append(args, "--rm")
+	}
+	for _, pair := range launchLabelPairs(cfg) {
+		args = append(args, "--label", pair)
+	}
+	managedEnv := containerEnv(cfg)
+	for _, env := range managedEnv {
+		if !showSecrets {
+			env = maskSecretEnv(env, cfg)
+		}
+		args = append(args, "-e", env)
+	}
+	// Forwarded shell variables render as bare keys: the docker CLI
+	// inherits their values, so they never appear in the argv.
+	for _, k := range forwardedEnvKeys(cfg, managedEnv) {
+		args = append(args, "-e", k)
+	}
+	if sock := socketBind(currentEngine); sock != "" && !cfg.NoDockerSock {
+		args = append(args, "-v", sock)
+	}
+	if !useSandboxVolumes(cfg) {
+		args = append(args, "-v", hostMountPath(cfg.mountWorkspace())+":"+WorkspaceMountPath+cfg.workspaceBindSuffix())
+	}
+	if cfg.stateDir != "" {
+		args = append(args, "-v", hostMountPath(cfg.stateDir)+":"+StateMountPath)
+	}
+	for _, m := range cfg.ExtraMounts {
+		args = append(args, "-v", m)
+	}
+	if cfg.ServerConfig != "" {
+		args = append(args, "-v", hostMountPath(cfg.ServerConfig)+":/app/config.toml:ro")
+	}
+	if cfg.Network != "" {
+		args = append(args, "--network", cfg.Network)
+	}
+	if cfg.Network != "host" {
+		args = append(args, "-p", fmt.Sprintf("%s:%d:%d", cfg.Bind, cfg.Port, DefaultPort))
+		args = append(args, "--add-host", "host.docker.internal:host-gateway")
+	}
+	if cfg.User != "" {
+		args = append(args, "--user", cfg.User)
+	}
+	if cfg.GPUs != "" {
+		args = append(args, "--gpus", cfg.GPUs)
+	}
+	if cfg.Platform != "" {
+		args = append(args, "--platform", cfg.Platform)
+	}
+	if cfg.ShmSize != "" {
+		args = append(args, "--shm-size", cfg.ShmSize)
+	}
+	if cfg.Memory != "" {
+		args = append(args, "--memory", cfg.Memory)
+	}
+	if cfg.CPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(cfg.CPUs, 'g', -1, 64))
+	}
+	for _, ul := range cfg.Ulimits {
+		args = append(args, "--ulimit", ul)
+	}
+	for _, opt := range cfg.SecurityOpts {
+		args = append(args, "--security-opt", opt)
+	}
+	if cfg.Hardened {
+		args = append(args, "--cap-drop", "ALL")
+		for _, cap := range hardenedCaps {
+			args = append(args, "--cap-add", cap)
+		}
+		args = append(args, "--security-opt", "no-new-privileges")
+	}
+	// User passthrough options come last, directly before the image, the
+	// position docker requires for container options.
+	args = append(args, cfg.DockerArgs...)
+	args = append(args, cfg.AppImage())
+	args = append(args, containerCmd(cfg)...)
+	return args
+}
+
+// maskSecretEnv hides the values of credential-bearing variables: anything
+// sensitiveEnvKey flags by name, plus everything that came from the secrets
+// file or secret references.
+func maskSecretEnv(env string, cfg *Config) string {
+	key, _, ok := strings.Cut(env, "=")
+	if !ok {
+		return env
+	}
+	if name, ok := strings.CutPrefix(key, "SANDBOX_ENV_"); ok {
+		if _, secret := cfg.sandboxSecrets[name]; secret || sensitiveEnvKey(name) {
+			return key + "=" + secretMask
+		}
+		return env
+	}
+	if sensitiveEnvKey(key) {
+		return key + "=" + secretMask
+	}
+	return env
+}
+
+// shellQuote renders args as one copy-pasteable shell command line.
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n\"'\\$`!*?[](){};&|<>~#") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// renderDockerCommand is the -dry-run entry point.
+func renderDockerCommand(cfg *Config, showSecrets bool) string {
+	return shellQuote(buildDockerArgs(cfg, showSecrets))
+}
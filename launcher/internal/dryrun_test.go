@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildDockerArgsMasksSecrets(t *testing.T) {
+	cfg := &Config{
+		Workspace:      "/home/u/proj",
+		Tag:            DefaultTag,
+		Port:           3000,
+		sandboxSecrets: map[string]string{"GH_TOKEN": "ghp_secret"},
+	}
+	cfg.LLM.APIKey = "sk-very-secret"
+
+	masked := strings.Join(buildDockerArgs(cfg, false), " ")
+	if strings.Contains(masked, "sk-very-secret") || strings.Contains(masked, "ghp_secret") {
+		t.Fatalf("secrets leaked into masked output: %s", masked)
+	}
+	if !strings.Contains(masked, "LLM_API_KEY="+secretMask) {
+		t.Errorf("API key not masked: %s", masked)
+	}
+
+	shown := strings.Join(buildDockerArgs(cfg, true), " ")
+	if !strings.Contains(shown, "sk-very-secret") {
+		t.Errorf("-show-secrets should keep values: %s", shown)
+	}
+}
+
+func TestBuildDockerArgsNoDockerSock(t *testing.T) {
+	cfg := &Config{Workspace: "/home/u/proj", Tag: DefaultTag, Port: 3000, Bind: "127.0.0.1"}
+	withSock := strings.Join(buildDockerArgs(cfg, false), " ")
+	if !strings.Contains(withSock, ":/var/run/docker.sock") {
+		t.Errorf("default args should mount the engine socket: %s", withSock)
+	}
+	if strings.Contains(withSock, "RUNTIME=local") {
+		t.Errorf("default args should not select the local runtime: %s", withSock)
+	}
+
+	cfg.NoDockerSock = true
+	without := strings.Join(buildDockerArgs(cfg, false), " ")
+	if strings.Contains(without, "docker.sock") {
+		t.Errorf("-no-docker-sock args still mount the socket: %s", without)
+	}
+	if !strings.Contains(without, "RUNTIME=local") {
+		t.Errorf("-no-docker-sock args should select the local runtime: %s", without)
+	}
+	if strings.Contains(without, "SANDBOX_RUNTIME_CONTAINER_IMAGE") {
+		t.Errorf("-no-docker-sock args should not name a runtime image: %s", without)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	got := shellQuote([]string{"docker", "run", "-e", "TASK=fix the 'bug'", "--name", "openhands-app"})
+	want := `docker run -e 'TASK=fix the '\''bug'\''' --name openhands-app`
+	if got != want {
+		t.Errorf("shellQuote = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// engine describes the container engine the launcher talks to. Podman's
+// compatibility API speaks the Docker protocol, so the same client works;
+// what differs is where the socket lives and a few container options.
+type engine struct {
+	Kind       string // "docker" or "podman"
+	SocketPath string // host path of the engine socket, for the socket bind
+	// RemoteHost is set instead of SocketPath when DOCKER_HOST points at a
+	// tcp:// (or ssh://) daemon: there is no local socket to mount, so the
+	// app container reaches the daemon through DOCKER_HOST itself.
+	RemoteHost string
+	Rootless   bool
+}
+
+// currentEngine is set by NewDockerClient and consulted when building
+// container configuration.
+var currentEngine = engine{Kind: "docker", SocketPath: "/var/run/docker.sock"}
+
+// socketOverride is the -docker-socket flag: an explicit engine socket path
+// that wins over every probe. Set by ValidateFlags.
+var socketOverride string
+
+// detectEngine works out which engine to talk to and over which host URL.
+// An explicit override (-docker-socket) wins, then DOCKER_HOST. Otherwise
+// the Docker sockets are probed - the standard path, then the rootless one
+// under XDG_RUNTIME_DIR - and after that the Podman sockets, rootless first,
+// so Podman-only machines work without being told to install Docker. The
+// empty host string means "let the client use its default".
+func detectEngine(override, dockerHost, xdgRuntimeDir string, exists func(string) bool) (string, engine) {
+	if override != "" {
+		return "unix://" + override, engineForSocket(override, xdgRuntimeDir)
+	}
+	if dockerHost != "" {
+		if path, ok := strings.CutPrefix(dockerHost, "unix://"); ok {
+			return "", engineForSocket(path, xdgRuntimeDir)
+		}
+		return "", engine{Kind: "docker", RemoteHost: dockerHost}
+	}
+	if exists("/var/run/docker.sock") {
+		return "", engine{Kind: "docker", SocketPath: "/var/run/docker.sock"}
+	}
+	if xdgRuntimeDir != "" {
+		if sock := filepath.Join(xdgRuntimeDir, "docker.sock"); exists(sock) {
+			return "unix://" + sock, engine{Kind: "docker", SocketPath: sock, Rootless: true}
+		}
+		if sock := filepath.Join(xdgRuntimeDir, "podman", "podman.sock"); exists(sock) {
+			return "unix://" + sock, engine{Kind: "podman", SocketPath: sock, Rootless: true}
+		}
+	}
+	if sock := "/run/podman/podman.sock"; exists(sock) {
+		return "unix://" + sock, engine{Kind: "podman", SocketPath: sock}
+	}
+	// Nothing found; fall through to the client default so the eventual
+	// connection error names the standard socket.
+	return "", engine{Kind: "docker", SocketPath: "/var/run/docker.sock"}
+}
+
+// engineForSocket classifies an explicit unix socket path.
+func engineForSocket(path, xdgRuntimeDir string) engine {
+	eng := engine{Kind: "docker", SocketPath: path}
+	if strings.Contains(path, "podman") {
+		eng.Kind = "podman"
+	}
+	eng.Rootless = xdgRuntimeDir != "" && strings.HasPrefix(path, xdgRuntimeDir)
+	return eng
+}
+
+// socketBind returns the bind mount that hands the engine socket to the app
+// container, or "" for a remote daemon where there is nothing to mount. The
+// in-container path stays /var/run/docker.sock either way, because that is
+// where the server expects it.
+func socketBind(eng engine) string {
+	if eng.SocketPath == "" {
+		return ""
+	}
+	return eng.SocketPath + ":/var/run/docker.sock"
+}
+
+// engineHint explains a daemon connection failure in engine-specific terms.
+func engineHint(eng engine) string {
+	if eng.Kind == "podman" {
+		return "the Podman API socket is not answering; start it with `systemctl --user start podman.socket`"
+	}
+	return "the daemon is not responding; start Docker Desktop, or `systemctl start docker` on Linux"
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func describeEngine(eng engine) string {
+	mode := ""
+	if eng.Rootless {
+		mode = " (rootless)"
+	}
+	target := eng.SocketPath
+	if eng.RemoteHost != "" {
+		target = eng.RemoteHost
+	}
+	return fmt.Sprintf("%s%s via %s", eng.Kind, mode, target)
+}
@@ -0,0 +1,69 @@
+package internal
+
+import "testing"
+
+func TestDetectEngine(t *testing.T) {
+	only := func(paths ...string) func(string) bool {
+		set := map[string]bool{}
+		for _, p := range paths {
+			set[p] = true
+		}
+		return func(p string) bool { return set[p] }
+	}
+
+	host, eng := detectEngine("", "", "/run/user/1000", only("/var/run/docker.sock"))
+	if host != "" || eng.Kind != "docker" {
+		t.Errorf("docker socket present: host=%q eng=%+v", host, eng)
+	}
+
+	host, eng = detectEngine("", "", "/run/user/1000", only("/run/user/1000/podman/podman.sock"))
+	if host != "unix:///run/user/1000/podman/podman.sock" || eng.Kind != "podman" || !eng.Rootless {
+		t.Errorf("rootless podman not detected: host=%q eng=%+v", host, eng)
+	}
+
+	host, eng = detectEngine("", "", "", only("/run/podman/podman.sock"))
+	if host != "unix:///run/podman/podman.sock" || eng.Kind != "podman" || eng.Rootless {
+		t.Errorf("rootful podman not detected: host=%q eng=%+v", host, eng)
+	}
+
+	// Explicit DOCKER_HOST wins and is left to the client's FromEnv.
+	host, eng = detectEngine("", "unix:///run/user/1000/podman/podman.sock", "/run/user/1000", only())
+	if host != "" || eng.Kind != "podman" || !eng.Rootless {
+		t.Errorf("DOCKER_HOST podman: host=%q eng=%+v", host, eng)
+	}
+
+	// Rootless Docker's socket is probed before the Podman ones.
+	host, eng = detectEngine("", "", "/run/user/1000", only("/run/user/1000/docker.sock", "/run/user/1000/podman/podman.sock"))
+	if host != "unix:///run/user/1000/docker.sock" || eng.Kind != "docker" || !eng.Rootless {
+		t.Errorf("rootless docker not detected: host=%q eng=%+v", host, eng)
+	}
+
+	// A tcp:// DOCKER_HOST means a remote daemon: nothing to mount.
+	host, eng = detectEngine("", "tcp://build-host:2376", "", only())
+	if host != "" || eng.RemoteHost != "tcp://build-host:2376" || eng.SocketPath != "" {
+		t.Errorf("remote DOCKER_HOST: host=%q eng=%+v", host, eng)
+	}
+	if socketBind(eng) != "" {
+		t.Errorf("remote daemon should have no socket bind, got %q", socketBind(eng))
+	}
+
+	// An explicit -docker-socket override wins over everything.
+	host, eng = detectEngine("/colima/docker.sock", "tcp://build-host:2376", "", only())
+	if host != "unix:///colima/docker.sock" || eng.SocketPath != "/colima/docker.sock" {
+		t.Errorf("override: host=%q eng=%+v", host, eng)
+	}
+
+	// Nothing found: default to docker so the error names the usual socket.
+	host, eng = detectEngine("", "", "", only())
+	if host != "" || eng.Kind != "docker" {
+		t.Errorf("fallback: host=%q eng=%+v", host, eng)
+	}
+}
+
+func TestSocketBind(t *testing.T) {
+	eng := engine{Kind: "podman", SocketPath: "/run/user/1000/podman/podman.sock"}
+	want := "/run/user/1000/podman/podman.sock:/var/run/docker.sock"
+	if got := socketBind(eng); got != want {
+		t.Errorf("socketBind = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// managedEnvNames are the variables the launcher itself sets on the app
+// container. An explicit -env of one of these still wins, but silently
+// overriding what another flag configured is surprising enough to warn
+// about.
+var managedEnvNames = map[string]bool{
+	"LLM_MODEL":                       true,
+	"LLM_API_KEY":                     true,
+	"LLM_BASE_URL":                    true,
+	"MAX_BUDGET_PER_TASK":             true,
+	"SANDBOX_RUNTIME_CONTAINER_IMAGE": true,
+	"SANDBOX_USER_ID":                 true,
+	"WORKSPACE_MOUNT_PATH":            true,
+	"TZ":                              true,
+}
+
+// resolveEnv merges an env file's variables with -env specs into the final
+// set passed to the app container. Specs are "KEY=VALUE", or a bare "KEY"
+// that inherits the value from the invoking shell via getenv (and is skipped
+// when unset, matching docker run). Flag specs win over the file; collisions
+// with launcher-managed variables are reported on warn but still honored.
+func resolveEnv(specs []string, fileVars map[string]string, getenv func(string) string, warn io.Writer) (map[string]string, error) {
+	env := make(map[string]string, len(fileVars)+len(specs))
+	for k, v := range fileVars {
+		env[k] = v
+	}
+	for _, spec := range specs {
+		key, value, hasValue := strings.Cut(spec, "=")
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("invalid -env %q: expected KEY or KEY=VALUE", spec)
+		}
+		if !hasValue {
+			value = getenv(key)
+			if value == "" {
+				continue
+			}
+		}
+		env[key] = value
+	}
+	for _, k := range sortedKeys(env) {
+		if managedEnvNames[k] {
+			fmt.Fprintf(warn, "Warning: -env %s overrides the value the launcher would set; the explicit value wins.\n", k)
+		}
+	}
+	return env, nil
+}
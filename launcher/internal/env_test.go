@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestResolveEnv(t *testing.T) {
+	getenv := func(key string) string {
+		return map[string]string{"FROM_SHELL": "shell-value"}[key]
+	}
+	fileVars := map[string]string{"LOG_ALL_EVENTS": "true", "SANDBOX_TIMEOUT": "120"}
+	var warn bytes.Buffer
+	env, err := resolveEnv(
+		[]string{"SANDBOX_TIMEOUT=300", "FROM_SHELL", "UNSET_IN_SHELL", "LLM_API_KEY=abc"},
+		fileVars, getenv, &warn,
+	)
+	if err != nil {
+		t.Fatalf("resolveEnv: %v", err)
+	}
+	if env["LOG_ALL_EVENTS"] != "true" {
+		t.Errorf("file variable lost: %v", env)
+	}
+	if env["SANDBOX_TIMEOUT"] != "300" {
+		t.Errorf("flag should win over file: got %q", env["SANDBOX_TIMEOUT"])
+	}
+	if env["FROM_SHELL"] != "shell-value" {
+		t.Errorf("bare KEY should inherit from the shell: got %q", env["FROM_SHELL"])
+	}
+	if _, ok := env["UNSET_IN_SHELL"]; ok {
+		t.Errorf("bare KEY unset in the shell should be skipped")
+	}
+	if !strings.Contains(warn.String(), "LLM_API_KEY") {
+		t.Errorf("expected a collision warning for LLM_API_KEY, got %q", warn.String())
+	}
+}
+
+func TestResolveEnvRejectsBadSpecs(t *testing.T) {
+	for _, spec := range []string{"", "=value", "BAD KEY=x"} {
+		if _, err := resolveEnv([]string{spec}, nil, func(string) string { return "" }, &bytes.Buffer{}); err == nil {
+			t.Errorf("spec %q: expected an error", spec)
+		}
+	}
+}
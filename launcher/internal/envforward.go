@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultForwardPrefixes are the env var families the Python app is
+// configured through; anything in the shell matching one of them is
+// forwarded so settings like LLM_TEMPERATURE or SANDBOX_TIMEOUT do not need
+// a dedicated flag.
+var defaultForwardPrefixes = []string{"LLM_", "SANDBOX_", "AGENT_", "SECURITY_"}
+
+// reservedForwardKeys are variables the launcher manages explicitly; they
+// are never forwarded from the shell, even when the launcher happens not to
+// set them this run, so the explicit handling (and the key-file secret
+// path) always wins.
+var reservedForwardKeys = map[string]bool{
+	"LLM_MODEL":                       true,
+	"LLM_API_KEY":                     true,
+	"LLM_API_KEY_FILE":                true,
+	"LLM_BASE_URL":                    true,
+	"LLM_API_VERSION":                 true,
+	"SANDBOX_RUNTIME_CONTAINER_IMAGE": true,
+	"SANDBOX_USER_ID":                 true,
+	"SANDBOX_VOLUMES":                 true,
+}
+
+// forwardPrefixes returns the active prefixes: the defaults plus any
+// -forward-env-prefix additions, or nothing under -no-env-forward.
+func forwardPrefixes(cfg *Config) []string {
+	if cfg.NoEnvForward {
+		return nil
+	}
+	return append(append([]string{}, defaultForwardPrefixes...), cfg.ForwardEnvPrefixes...)
+}
+
+// forwardedEnvKeys picks the shell variables to forward into the app
+// container: set, matching a forward prefix, not reserved, and not already
+// among the managed KEY=VALUE entries the launcher built - explicit flags
+// and -e settings always win over the inherited environment. The result is
+// sorted and, under -verbose, listed.
+func forwardedEnvKeys(cfg *Config, managed []string) []string {
+	prefixes := forwardPrefixes(cfg)
+	if len(prefixes) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, kv := range managed {
+		k, _, _ := strings.Cut(kv, "=")
+		seen[k] = true
+	}
+	var keys []string
+	for _, kv := range os.Environ() {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || v == "" || seen[k] || reservedForwardKeys[k] {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+				break
+			}
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		debugf("Forwarding environment variables: %s", strings.Join(keys, ", "))
+	}
+	return keys
+}
@@ -0,0 +1,52 @@
+package internal
+
+import "testing"
+
+func containsKey(keys []string, want string) bool {
+	for _, k := range keys {
+		if k == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestForwardedEnvKeys(t *testing.T) {
+	t.Setenv("LLM_TEMPERATURE", "0.2")
+	t.Setenv("SANDBOX_TIMEOUT", "120")
+	t.Setenv("AGENT_MEMORY_ENABLED", "true")
+	t.Setenv("LLM_API_KEY", "sk-secret")
+	t.Setenv("UNRELATED_VAR", "x")
+	managed := []string{"SANDBOX_TIMEOUT=60", "LLM_MODEL=openai/gpt-4o"}
+
+	keys := forwardedEnvKeys(&Config{}, managed)
+	for _, want := range []string{"LLM_TEMPERATURE", "AGENT_MEMORY_ENABLED"} {
+		if !containsKey(keys, want) {
+			t.Errorf("forwardedEnvKeys missing %s: %v", want, keys)
+		}
+	}
+	if containsKey(keys, "SANDBOX_TIMEOUT") {
+		t.Errorf("SANDBOX_TIMEOUT is managed explicitly and must not be forwarded: %v", keys)
+	}
+	if containsKey(keys, "LLM_API_KEY") {
+		t.Errorf("LLM_API_KEY is reserved and must not be forwarded: %v", keys)
+	}
+	if containsKey(keys, "UNRELATED_VAR") {
+		t.Errorf("UNRELATED_VAR matches no prefix and must not be forwarded: %v", keys)
+	}
+}
+
+func TestForwardedEnvKeysExtraPrefix(t *testing.T) {
+	t.Setenv("OH_DEBUG", "1")
+	keys := forwardedEnvKeys(&Config{ForwardEnvPrefixes: []string{"OH_"}}, nil)
+	if !containsKey(keys, "OH_DEBUG") {
+		t.Errorf("-forward-env-prefix OH_ should forward OH_DEBUG: %v", keys)
+	}
+}
+
+func TestForwardedEnvKeysDisabled(t *testing.T) {
+	t.Setenv("LLM_TEMPERATURE", "0.2")
+	if keys := forwardedEnvKeys(&Config{NoEnvForward: true}, nil); keys != nil {
+		t.Errorf("-no-env-forward should forward nothing, got %v", keys)
+	}
+}
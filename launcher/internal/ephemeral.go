@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// prepareEphemeral clones the workspace into a temporary copy and returns the
+// copy's path. The agent then runs against the copy and can never touch the
+// original; finishEphemeral presents the outcome afterwards.
+func prepareEphemeral(workspace string) (string, error) {
+	tmp, err := os.MkdirTemp("", "openhands-ephemeral-*")
+	if err != nil {
+		return "", err
+	}
+	copyPath := filepath.Join(tmp, filepath.Base(workspace))
+	fmt.Fprintf(os.Stderr, "Ephemeral mode: cloning workspace to %s\n", copyPath)
+	if err := copyTree(workspace, copyPath); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("cloning workspace: %w", err)
+	}
+	return copyPath, nil
+}
+
+// finishEphemeral shows what the agent changed in the ephemeral copy and
+// asks whether to apply it back to the original, export it as a patch, or
+// discard it. The copy's parent temp directory is removed afterwards.
+func finishEphemeral(original, copy string) error {
+	defer os.RemoveAll(filepath.Dir(copy))
+	stat := ephemeralDiff(original, copy, "--stat")
+	if len(strings.TrimSpace(string(stat))) == 0 {
+		fmt.Fprintln(os.Stderr, "Ephemeral workspace is unchanged; nothing to apply.")
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "\nChanges made in the ephemeral workspace:\n%s\n", stat)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprint(os.Stderr, "[a]pply to the original, [e]xport as patch, [v]iew diff, [d]iscard? ")
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "No terminal input; changes discarded.")
+			return nil
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "a", "apply":
+			if err := syncTree(copy, original); err != nil {
+				return fmt.Errorf("applying changes: %w", err)
+			}
+			fmt.Fprintln(os.Stderr, "Changes applied to the original workspace.")
+			return nil
+		case "e", "export":
+			path := filepath.Base(original) + ".patch"
+			if err := os.WriteFile(path, ephemeralDiff(original, copy), 0o644); err != nil {
+				return fmt.Errorf("writing patch: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Patch written to %s\n", path)
+			return nil
+		case "v", "view":
+			os.Stderr.Write(ephemeralDiff(original, copy))
+		case "d", "discard":
+			fmt.Fprintln(os.Stderr, "Changes discarded.")
+			return nil
+		}
+	}
+}
+
+// ephemeralDiff diffs the two trees with git (which needs no repository in
+// --no-index mode). git diff exits 1 on differences, so the error is
+// deliberately ignored; an empty result means no changes or no git.
+func ephemeralDiff(original, copy string, extra ...string) []byte {
+	args := append([]string{"diff", "--no-index"}, extra...)
+	args = append(args, "--", original, copy)
+	out, _ := exec.Command("git", args...).Output()
+	return out
+}
+
+// copyTree copies a directory tree: regular files, directories and symlinks,
+// preserving permissions. Anything else (sockets, devices) is skipped.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// syncTree makes dst mirror src: changed and new entries are copied over,
+// entries that no longer exist in src are deleted. The .git directory is left
+// alone on both sides so applying never rewrites repository history.
+func syncTree(src, dst string) error {
+	if err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == ".git" || strings.HasPrefix(rel, ".git"+string(os.PathSeparator)) {
+			return fs.SkipDir
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			os.Remove(target)
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	}); err != nil {
+		return err
+	}
+	// Deletion pass: anything in dst that src no longer has.
+	var stale []string
+	if err := filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dst, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if rel == ".git" || strings.HasPrefix(rel, ".git"+string(os.PathSeparator)) {
+			return fs.SkipDir
+		}
+		if _, err := os.Lstat(filepath.Join(src, rel)); os.IsNotExist(err) {
+			stale = append(stale, path)
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, path := range stale {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCopyTreeAndSyncTree(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{
+		"main.go":        "package main\n",
+		"sub/helper.go":  "package sub\n",
+		".git/config":    "[core]\n",
+		"docs/README.md": "readme\n",
+	})
+
+	copyDir := filepath.Join(t.TempDir(), "copy")
+	if err := copyTree(src, copyDir); err != nil {
+		t.Fatalf("copyTree: %v", err)
+	}
+	if raw, err := os.ReadFile(filepath.Join(copyDir, "sub/helper.go")); err != nil || string(raw) != "package sub\n" {
+		t.Fatalf("copied file wrong: %q, %v", raw, err)
+	}
+
+	// Simulate the agent's session in the copy: edit, add, delete.
+	writeTree(t, copyDir, map[string]string{
+		"main.go": "package main // edited\n",
+		"new.go":  "package main\n",
+	})
+	if err := os.Remove(filepath.Join(copyDir, "docs/README.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syncTree(copyDir, src); err != nil {
+		t.Fatalf("syncTree: %v", err)
+	}
+	if raw, _ := os.ReadFile(filepath.Join(src, "main.go")); string(raw) != "package main // edited\n" {
+		t.Errorf("edit not applied: %q", raw)
+	}
+	if _, err := os.Stat(filepath.Join(src, "new.go")); err != nil {
+		t.Errorf("new file not applied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(src, "docs/README.md")); !os.IsNotExist(err) {
+		t.Errorf("deletion not applied")
+	}
+	if _, err := os.Stat(filepath.Join(src, ".git/config")); err != nil {
+		t.Errorf(".git must be left alone: %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// eventsClient talks to the backend's conversation REST API. It is used by
+// the terminal view and the `events` command; the browser UI uses the
+// socket.io stream instead, but polling keeps the CLI dependency-free.
+type eventsClient struct {
+	base string // e.g. http://localhost:3000
+	http *http.Client
+}
+
+func newEventsClient(base string) *eventsClient {
+	return &eventsClient{
+		base: strings.TrimRight(base, "/"),
+		http: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Event is one entry of the agent event stream. Only the commonly present
+// fields are typed; everything else stays in Raw.
+type Event struct {
+	ID          int                    `json:"id"`
+	Timestamp   string                 `json:"timestamp"`
+	Source      string                 `json:"source"`
+	Action      string                 `json:"action"`
+	Observation string                 `json:"observation"`
+	Message     string                 `json:"message"`
+	Raw         map[string]interface{} `json:"-"`
+}
+
+// Kind returns the event's action or observation type.
+func (e *Event) Kind() string {
+	if e.Action != "" {
+		return e.Action
+	}
+	return e.Observation
+}
+
+// Summary renders a single-line human-readable form of the event.
+func (e *Event) Summary() string {
+	kind := e.Kind()
+	msg := e.Message
+	if msg == "" {
+		if args, ok := e.Raw["args"].(map[string]interface{}); ok {
+			if cmd, ok := args["command"].(string); ok {
+				msg = cmd
+			} else if c, ok := args["content"].(string); ok {
+				msg = c
+			}
+		}
+	}
+	msg = strings.ReplaceAll(msg, "\n", " ")
+	const max = 120
+	if len(msg) > max {
+		msg = msg[:max] + "..."
+	}
+	return fmt.Sprintf("[%s] %s: %s", e.Source, kind, msg)
+}
+
+// LatestConversation returns the ID of the most recently active
+// conversation.
+func (c *eventsClient) LatestConversation() (string, error) {
+	resp, err := c.http.Get(c.base + "/api/conversations")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("listing conversations: unexpected status %s", resp.Status)
+	}
+	// The endpoint has returned both a bare list and a paginated object
+	// across versions; accept either.
+	var page struct {
+		Results []struct {
+			ConversationID string `json:"conversation_id"`
+		} `json:"results"`
+	}
+	var list []struct {
+		ConversationID string `json:"conversation_id"`
+	}
+	dec := json.NewDecoder(resp.Body)
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(raw, &page); err == nil && len(page.Results) > 0 {
+		return page.Results[0].ConversationID, nil
+	}
+	if err := json.Unmarshal(raw, &list); err == nil && len(list) > 0 {
+		return list[0].ConversationID, nil
+	}
+	return "", fmt.Errorf("no conversations yet")
+}
+
+// Events returns events of a conversation with an ID greater than afterID.
+func (c *eventsClient) Events(convID string, afterID int) ([]Event, error) {
+	u := fmt.Sprintf("%s/api/conversations/%s/events?start_id=%d", c.base, url.PathEscape(convID), afterID+1)
+	resp, err := c.http.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching events: unexpected status %s", resp.Status)
+	}
+	var raws []map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&raws); err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(raws))
+	for _, raw := range raws {
+		buf, _ := json.Marshal(raw)
+		var ev Event
+		if err := json.Unmarshal(buf, &ev); err != nil {
+			continue
+		}
+		ev.Raw = raw
+		events = append(events, ev)
+	}
+	return events, nil
+}
+
+// SetAgentState posts an agent control action (paused, running, stopped),
+// the same change_agent_state event the web UI's pause/resume buttons send.
+func (c *eventsClient) SetAgentState(convID, state string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"action": "change_agent_state",
+		"args":   map[string]interface{}{"agent_state": state},
+	})
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/api/conversations/%s/events", c.base, url.PathEscape(convID))
+	resp, err := c.http.Post(u, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("setting agent state: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// SendMessage posts a user message into the conversation.
+func (c *eventsClient) SendMessage(convID, content string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"action": "message",
+		"args":   map[string]interface{}{"content": content},
+	})
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/api/conversations/%s/events", c.base, url.PathEscape(convID))
+	resp, err := c.http.Post(u, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sending message: unexpected status %s", resp.Status)
+	}
+	return nil
+}
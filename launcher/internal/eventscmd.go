@@ -0,0 +1,139 @@
+package internal
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// multiFlag collects a repeatable string flag.
+type multiFlag []string
+
+func (m *multiFlag) String() string     { return strings.Join(*m, ",") }
+func (m *multiFlag) Set(v string) error { *m = append(*m, v); return nil }
+
+// CmdEvents implements `openhands events`: it prints the structured agent
+// event feed of the current conversation, optionally following it, filtered
+// by event fields. Typical use:
+//
+//	openhands events --filter action=run,edit
+//
+// answers "what commands did the agent actually run?".
+func CmdEvents(args []string) int {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	conv := fs.String("conversation", "", "conversation ID (defaults to the most recent)")
+	follow := fs.Bool("f", false, "keep following new events")
+	asJSON := fs.Bool("json", false, "print raw events as JSON lines")
+	since := fs.Duration("since", 0, "only show events newer than this age, e.g. 30m")
+	var filters multiFlag
+	fs.Var(&filters, "filter", "field=value[,value] filter, e.g. action=run,edit (repeatable)")
+	fs.Parse(args)
+
+	match, err := parseEventFilters(filters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 2
+	}
+	var cutoff time.Time
+	if *since > 0 {
+		cutoff = time.Now().Add(-*since)
+	}
+
+	client := newEventsClient(fmt.Sprintf("http://localhost:%d", *port))
+	convID := *conv
+	if convID == "" {
+		if convID, err = client.LatestConversation(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+
+	lastID := -1
+	for {
+		events, err := client.Events(convID, lastID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		for i := range events {
+			ev := &events[i]
+			if ev.ID > lastID {
+				lastID = ev.ID
+			}
+			if !match(ev) || !eventAfter(ev, cutoff) {
+				continue
+			}
+			if *asJSON {
+				raw, _ := json.Marshal(ev.Raw)
+				fmt.Println(string(raw))
+			} else {
+				fmt.Printf("%6d  %s\n", ev.ID, ev.Summary())
+			}
+		}
+		if !*follow {
+			return 0
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// parseEventFilters turns repeated field=value[,value] flags into a matcher.
+// Values for the same field are OR'ed, different fields are AND'ed. Actions
+// and observations are separate fields, so `--filter action=run` shows the
+// commands and `--filter observation=run` their results.
+func parseEventFilters(filters []string) (func(*Event) bool, error) {
+	wanted := make(map[string]map[string]bool)
+	for _, f := range filters {
+		field, values, ok := strings.Cut(f, "=")
+		if !ok || field == "" || values == "" {
+			return nil, fmt.Errorf("invalid -filter %q (expected field=value[,value])", f)
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		if wanted[field] == nil {
+			wanted[field] = make(map[string]bool)
+		}
+		for _, v := range strings.Split(values, ",") {
+			wanted[field][strings.ToLower(strings.TrimSpace(v))] = true
+		}
+	}
+	return func(ev *Event) bool {
+		for field, values := range wanted {
+			var have string
+			switch field {
+			case "action":
+				have = ev.Action
+			case "observation":
+				have = ev.Observation
+			case "source":
+				have = ev.Source
+			default:
+				if v, ok := ev.Raw[field].(string); ok {
+					have = v
+				}
+			}
+			if !values[strings.ToLower(have)] {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// eventAfter reports whether the event is newer than the cutoff. Events
+// without a parseable timestamp pass, so filtering never hides data because
+// of a format change.
+func eventAfter(ev *Event, cutoff time.Time) bool {
+	if cutoff.IsZero() || ev.Timestamp == "" {
+		return true
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999"} {
+		if ts, err := time.Parse(layout, ev.Timestamp); err == nil {
+			return ts.After(cutoff)
+		}
+	}
+	return true
+}
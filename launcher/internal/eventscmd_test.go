@@ -0,0 +1,34 @@
+package internal
+
+import "testing"
+
+func TestParseEventFilters(t *testing.T) {
+	match, err := parseEventFilters([]string{"action=run,edit", "source=agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !match(&Event{Action: "run", Source: "agent"}) {
+		t.Error("run/agent should match")
+	}
+	if !match(&Event{Action: "edit", Source: "agent"}) {
+		t.Error("edit/agent should match")
+	}
+	if match(&Event{Action: "run", Source: "user"}) {
+		t.Error("wrong source should not match")
+	}
+	if match(&Event{Action: "browse", Source: "agent"}) {
+		t.Error("unlisted action should not match")
+	}
+	if match(&Event{Observation: "run", Source: "agent"}) {
+		t.Error("observations are not actions")
+	}
+}
+
+func TestParseEventFiltersRejectsMalformed(t *testing.T) {
+	if _, err := parseEventFilters([]string{"action"}); err == nil {
+		t.Error("expected an error for a filter without '='")
+	}
+	if _, err := parseEventFilters([]string{"=run"}); err == nil {
+		t.Error("expected an error for a filter without a field")
+	}
+}
@@ -0,0 +1,27 @@
+package internal
+
+import "errors"
+
+// exitCodeError carries the process exit status the launcher should end
+// with, so scripts can tell a server crash (the container's own code) from a
+// launcher-side failure (1). Containers killed by a signal already report
+// the conventional 128+signum as their status.
+type exitCodeError struct {
+	code int
+	msg  string
+}
+
+func (e *exitCodeError) Error() string { return e.msg }
+
+// ExitCode maps an error from Run to the launcher's exit status: 0 for nil,
+// the embedded status for container exits, and 1 for everything else.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var ee *exitCodeError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+	return 1
+}
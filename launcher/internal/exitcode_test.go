@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	if got := ExitCode(nil); got != 0 {
+		t.Errorf("nil error: got %d", got)
+	}
+	if got := ExitCode(errors.New("boom")); got != 1 {
+		t.Errorf("plain error: got %d", got)
+	}
+	cause := &exitCodeError{code: 137, msg: "killed"}
+	if got := ExitCode(cause); got != 137 {
+		t.Errorf("exit code error: got %d", got)
+	}
+	wrapped := fmt.Errorf("while waiting: %w", cause)
+	if got := ExitCode(wrapped); got != 137 {
+		t.Errorf("wrapped exit code error: got %d", got)
+	}
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// knownExperiments names the experimental behaviors that can ship dark and
+// be toggled per launch. Entries are removed once a behavior either becomes
+// the default or is dropped; enabling an unknown name is an error so typos
+// do not silently run the boring code path.
+var knownExperiments = map[string]string{
+	"warm-pool-handoff": "hand conversations a container from `openhands pool` instead of starting one cold",
+	"sync-mounts":       "use file-synchronization instead of bind mounts for the workspace",
+	"sdk-docker":        "drive the sandbox through the Docker SDK directly rather than the server's runtime layer",
+}
+
+// validateExperiments resolves the requested experiment names into a set,
+// rejecting unknown ones with the list of valid names.
+func validateExperiments(names []string) (map[string]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if _, ok := knownExperiments[name]; !ok {
+			return nil, fmt.Errorf("unknown experiment %q; known experiments: %s", name, strings.Join(experimentNames(), ", "))
+		}
+		enabled[name] = true
+	}
+	return enabled, nil
+}
+
+func experimentNames() []string {
+	names := make([]string, 0, len(knownExperiments))
+	for name := range knownExperiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExperimentEnabled reports whether the named experiment was turned on via
+// flag or config. Call sites guard their experimental branch with this, so
+// the stable path stays the default.
+func (c *Config) ExperimentEnabled(name string) bool {
+	return c.experiments[name]
+}
@@ -0,0 +1,22 @@
+package internal
+
+import "testing"
+
+func TestValidateExperiments(t *testing.T) {
+	enabled, err := validateExperiments([]string{"warm-pool-handoff", " sync-mounts "})
+	if err != nil {
+		t.Fatalf("validateExperiments: %v", err)
+	}
+	if !enabled["warm-pool-handoff"] || !enabled["sync-mounts"] {
+		t.Errorf("enabled = %v", enabled)
+	}
+
+	if _, err := validateExperiments([]string{"warp-drive"}); err == nil {
+		t.Error("unknown experiment should be rejected")
+	}
+
+	cfg := &Config{experiments: enabled}
+	if !cfg.ExperimentEnabled("sync-mounts") || cfg.ExperimentEnabled("sdk-docker") {
+		t.Errorf("ExperimentEnabled misreports: %v", cfg.experiments)
+	}
+}
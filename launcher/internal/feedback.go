@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// CmdFeedback implements `openhands feedback [CONVERSATION-ID]`: it packages
+// the conversation's trajectory and posts it to the server's feedback
+// endpoint, the same data the web UI's thumbs up/down collects. Because the
+// trajectory contains the full exchange - commands, file contents, model
+// output - submission always requires consent (-yes skips the prompt for
+// scripted use).
+func CmdFeedback(args []string) int {
+	fs := flag.NewFlagSet("feedback", flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	rating := fs.String("rating", "", "up or down (required)")
+	comment := fs.String("comment", "", "free-form comment to attach")
+	yes := fs.Bool("yes", false, "skip the consent prompt")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands feedback [flags] [CONVERSATION-ID]\n\nWithout an ID the most recent conversation is used.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var polarity string
+	switch *rating {
+	case "up":
+		polarity = "positive"
+	case "down":
+		polarity = "negative"
+	default:
+		fmt.Fprintln(os.Stderr, "openhands: -rating must be up or down")
+		return 2
+	}
+
+	client := newEventsClient(fmt.Sprintf("http://localhost:%d", *port))
+	convID := fs.Arg(0)
+	if convID == "" {
+		var err error
+		if convID, err = client.LatestConversation(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	events, err := client.Events(convID, -1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: fetching trajectory: %v\n", err)
+		return 1
+	}
+	if !*yes {
+		fmt.Fprintf(os.Stderr, "This submits the full trajectory of conversation %s (%d events, including commands and file contents) along with your rating. Continue? [y/N] ", convID, len(events))
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(os.Stderr, "Aborted; nothing was sent.")
+			return 1
+		}
+	}
+	trajectory := make([]map[string]interface{}, 0, len(events))
+	for i := range events {
+		trajectory = append(trajectory, events[i].Raw)
+	}
+	if err := client.SubmitFeedback(convID, polarity, *comment, trajectory); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(os.Stderr, "Feedback submitted. Thanks!")
+	return 0
+}
+
+// SubmitFeedback posts a rating plus trajectory to the conversation's
+// feedback endpoint.
+func (c *eventsClient) SubmitFeedback(convID, polarity, comment string, trajectory []map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"polarity":    polarity,
+		"feedback":    comment,
+		"permissions": "private",
+		"trajectory":  trajectory,
+	})
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("%s/api/conversations/%s/submit-feedback", c.base, url.PathEscape(convID))
+	resp, err := c.http.Post(u, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("submitting feedback: unexpected status %s", resp.Status)
+	}
+	return nil
+}
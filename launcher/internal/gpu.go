@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// gpuRequest is a parsed -gpus value: either a device count (-1 meaning all)
+// or an explicit device list, mirroring the subset of docker's --gpus syntax
+// people actually use.
+type gpuRequest struct {
+	Count     int
+	DeviceIDs []string
+}
+
+// parseGPUs validates a -gpus value. Accepted forms are "all", a positive
+// device count, and "device=ID[,ID...]" with numeric indexes or GPU UUIDs.
+func parseGPUs(spec string) (*gpuRequest, error) {
+	if spec == "all" {
+		return &gpuRequest{Count: -1}, nil
+	}
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid -gpus %q: the device count must be positive (or \"all\")", spec)
+		}
+		return &gpuRequest{Count: n}, nil
+	}
+	if list, ok := strings.CutPrefix(spec, "device="); ok {
+		ids := strings.Split(list, ",")
+		for _, id := range ids {
+			if id == "" {
+				return nil, fmt.Errorf("invalid -gpus %q: empty device id", spec)
+			}
+		}
+		return &gpuRequest{DeviceIDs: ids}, nil
+	}
+	return nil, fmt.Errorf("invalid -gpus %q: expected \"all\", a count, or device=ID[,ID...]", spec)
+}
+
+// deviceRequest converts the parsed value to what the engine API expects.
+func (g *gpuRequest) deviceRequest() container.DeviceRequest {
+	return container.DeviceRequest{
+		Count:        g.Count,
+		DeviceIDs:    g.DeviceIDs,
+		Capabilities: [][]string{{"gpu"}},
+	}
+}
+
+// checkGPURuntime verifies the daemon can actually satisfy a GPU request, so
+// a missing nvidia runtime fails with an actionable message before launch
+// instead of docker's create error.
+func checkGPURuntime(ctx context.Context, cli *client.Client) error {
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("querying daemon info for GPU support: %w", err)
+	}
+	if _, ok := info.Runtimes["nvidia"]; !ok {
+		return fmt.Errorf("-gpus was requested but the daemon has no nvidia runtime; install the NVIDIA Container Toolkit (nvidia-ctk runtime configure) and restart the daemon")
+	}
+	return nil
+}
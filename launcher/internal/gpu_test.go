@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGPUs(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    *gpuRequest
+		wantErr bool
+	}{
+		{spec: "all", want: &gpuRequest{Count: -1}},
+		{spec: "2", want: &gpuRequest{Count: 2}},
+		{spec: "device=0", want: &gpuRequest{DeviceIDs: []string{"0"}}},
+		{spec: "device=0,1", want: &gpuRequest{DeviceIDs: []string{"0", "1"}}},
+		{spec: "device=GPU-aaaa", want: &gpuRequest{DeviceIDs: []string{"GPU-aaaa"}}},
+		{spec: "0", wantErr: true},
+		{spec: "-1", wantErr: true},
+		{spec: "device=", wantErr: true},
+		{spec: "device=0,,1", wantErr: true},
+		{spec: "everything", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseGPUs(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseGPUs(%q): expected an error, got %+v", tt.spec, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseGPUs(%q): %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseGPUs(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestBuildDockerArgsGPUs(t *testing.T) {
+	cfg := &Config{Workspace: "/ws", Tag: DefaultTag, Port: 3000, Bind: "127.0.0.1", GPUs: "all"}
+	args := buildDockerArgs(cfg, false)
+	found := false
+	for i, a := range args {
+		if a == "--gpus" && i+1 < len(args) && args[i+1] == "all" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("buildDockerArgs: missing --gpus all in %v", args)
+	}
+}
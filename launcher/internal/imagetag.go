@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// parseImageTag extracts the tag from an image reference, or "" when the
+// reference has none. Digests are not tags: for "img@sha256:..." and
+// "img:tag@sha256:..." the digest part is ignored. A colon inside the
+// registry host ("registry:5000/img") is not mistaken for a tag separator.
+func parseImageTag(ref string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	slash := strings.LastIndex(ref, "/")
+	colon := strings.LastIndex(ref, ":")
+	if colon > slash {
+		return ref[colon+1:]
+	}
+	return ""
+}
+
+// warnRuntimeSkew compares the app tag with an explicitly configured runtime
+// image and flags a version mismatch. Skewed app/runtime versions are a
+// frequent source of baffling runtime failures, because the server and the
+// sandbox client must speak the same protocol.
+func warnRuntimeSkew(appTag, runtimeRef string) {
+	if runtimeRef == "" {
+		return
+	}
+	if _, ok := digestTag(appTag); ok {
+		// A digest names no version to compare against.
+		return
+	}
+	tag := parseImageTag(runtimeRef)
+	if tag == "" || tag == "latest" {
+		return
+	}
+	version := strings.TrimSuffix(tag, RuntimeTagSuffix)
+	if version == appTag {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: app image tag %q and runtime image %q disagree on the version; mismatched app/runtime versions cause hard-to-diagnose failures. Align them or drop sandbox_image to derive the runtime tag automatically.\n", appTag, runtimeRef)
+}
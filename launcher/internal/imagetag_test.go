@@ -0,0 +1,24 @@
+package internal
+
+import "testing"
+
+func TestParseImageTag(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"openhands/runtime:0.62-nikolaik", "0.62-nikolaik"},
+		{"docker.openhands.dev/openhands/openhands:0.62", "0.62"},
+		{"ghcr.io/all-hands-ai/openhands:latest", "latest"},
+		{"registry:5000/team/runtime", ""},
+		{"registry:5000/team/runtime:1.2", "1.2"},
+		{"openhands/runtime@sha256:deadbeef", ""},
+		{"openhands/runtime:0.62@sha256:deadbeef", "0.62"},
+		{"runtime", ""},
+	}
+	for _, tt := range tests {
+		if got := parseImageTag(tt.ref); got != tt.want {
+			t.Errorf("parseImageTag(%q) = %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
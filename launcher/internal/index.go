@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CmdIndex implements `openhands index [WORKSPACE]`: it pre-builds the
+// repository context (file map and key documentation excerpts) and caches it
+// in the state directory, where the server's context building picks it up.
+// On large monorepos this moves minutes of warm-up out of the first
+// conversation.
+func CmdIndex(args []string) int {
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	force := fs.Bool("force", false, "rebuild even if the cached index is current")
+	fs.Parse(args)
+
+	workspace := fs.Arg(0)
+	if workspace == "" {
+		workspace = "."
+	}
+	abs, err := filepath.Abs(workspace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	path, err := indexCachePath(abs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	head := gitHead(abs)
+	if !*force && head != "" {
+		if cached, err := loadRepoIndex(path); err == nil && cached.GitHead == head {
+			fmt.Printf("Index for %s is current (%d files); use -force to rebuild.\n", abs, len(cached.Files))
+			return 0
+		}
+	}
+
+	start := time.Now()
+	idx, err := buildRepoIndex(abs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	raw, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Indexed %d files and %d key documents in %s; cached at %s\n",
+		len(idx.Files), len(idx.Docs), time.Since(start).Round(time.Millisecond), path)
+	return 0
+}
+
+// repoIndex is the cached repository context.
+type repoIndex struct {
+	Workspace string            `json:"workspace"`
+	GitHead   string            `json:"git_head,omitempty"`
+	BuiltAt   time.Time         `json:"built_at"`
+	Files     []string          `json:"files"`
+	Docs      map[string]string `json:"docs"` // path -> leading excerpt
+}
+
+// indexCachePath keys the cache file by workspace path.
+func indexCachePath(workspace string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	cacheDir := filepath.Join(dir, "index")
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(workspace))
+	return filepath.Join(cacheDir, hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func loadRepoIndex(path string) (*repoIndex, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var idx repoIndex
+	if err := json.Unmarshal(raw, &idx); err != nil {
+		return nil, err
+	}
+	return &idx, nil
+}
+
+// maxIndexedFiles caps the file map so a vendored node_modules cannot blow
+// up the cache.
+const maxIndexedFiles = 50000
+
+// buildRepoIndex lists tracked files (falling back to a filtered walk
+// outside git) and captures the head of well-known documentation files.
+func buildRepoIndex(workspace string) (*repoIndex, error) {
+	idx := &repoIndex{
+		Workspace: workspace,
+		GitHead:   gitHead(workspace),
+		BuiltAt:   time.Now().UTC(),
+		Docs:      make(map[string]string),
+	}
+
+	files, err := listRepoFiles(workspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > maxIndexedFiles {
+		files = files[:maxIndexedFiles]
+	}
+	idx.Files = files
+
+	for _, f := range files {
+		base := strings.ToUpper(filepath.Base(f))
+		switch {
+		case strings.HasPrefix(base, "README"),
+			strings.HasPrefix(base, "CONTRIBUTING"),
+			strings.HasPrefix(base, "ARCHITECTURE"),
+			base == "DEVELOPMENT.MD", base == "MAKEFILE":
+			idx.Docs[f] = fileHead(filepath.Join(workspace, f), 4096)
+		}
+	}
+	return idx, nil
+}
+
+// listRepoFiles prefers `git ls-files` so ignore rules are respected.
+func listRepoFiles(workspace string) ([]string, error) {
+	out, err := exec.Command("git", "-C", workspace, "ls-files").Output()
+	if err == nil {
+		var files []string
+		for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+			if line != "" {
+				files = append(files, line)
+			}
+		}
+		return files, nil
+	}
+	var files []string
+	err = filepath.WalkDir(workspace, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			switch name {
+			case ".git", "node_modules", ".venv", "venv", "__pycache__", "target", "dist", "build":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(workspace, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		if len(files) >= maxIndexedFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return files, err
+}
+
+// fileHead returns up to n bytes from the start of a file.
+func fileHead(path string, n int) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	buf := make([]byte, n)
+	read, _ := f.Read(buf)
+	return string(buf[:read])
+}
+
+// gitHead returns the workspace's HEAD commit, or "" outside git.
+func gitHead(workspace string) string {
+	out, err := exec.Command("git", "-C", workspace, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
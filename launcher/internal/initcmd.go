@@ -0,0 +1,181 @@
+package internal
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CmdInit implements `openhands init [WORKSPACE]`: it scaffolds a
+// .openhands/ directory (repo microagent, setup script stub, cli.toml) from
+// a few questions about the project, so a repository becomes agent-ready in
+// one step. Existing files are never overwritten.
+func CmdInit(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "skip the questions and use generic defaults")
+	fs.Parse(args)
+
+	workspace := fs.Arg(0)
+	if workspace == "" {
+		workspace = "."
+	}
+	abs, err := filepath.Abs(workspace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	answers := initAnswers{
+		Language: "unknown",
+		Test:     "",
+		Lint:     "",
+	}
+	if !*yes {
+		reader := bufio.NewReader(os.Stdin)
+		answers.Language = ask(reader, "Main language of the project", guessLanguage(abs))
+		answers.Test = ask(reader, "Command that runs the tests (empty if none)", "")
+		answers.Lint = ask(reader, "Command that runs the linter (empty if none)", "")
+	}
+
+	created, err := scaffoldRepo(abs, answers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if len(created) == 0 {
+		fmt.Println("Nothing to do; .openhands/ is already set up.")
+		return 0
+	}
+	for _, path := range created {
+		fmt.Printf("Created %s\n", path)
+	}
+	fmt.Println("Review the generated files and commit them so the whole team benefits.")
+	reportLocalModels(os.Stdout, detectLocalModels())
+	return 0
+}
+
+type initAnswers struct {
+	Language string
+	Test     string
+	Lint     string
+}
+
+// ask prompts with a default value.
+func ask(reader *bufio.Reader, prompt, def string) string {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", prompt, def)
+	} else {
+		fmt.Printf("%s: ", prompt)
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return def
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def
+	}
+	return line
+}
+
+// guessLanguage makes a cheap guess from well-known manifest files, used as
+// the question's default.
+func guessLanguage(workspace string) string {
+	for file, lang := range map[string]string{
+		"go.mod":           "Go",
+		"pyproject.toml":   "Python",
+		"setup.py":         "Python",
+		"package.json":     "JavaScript/TypeScript",
+		"Cargo.toml":       "Rust",
+		"pom.xml":          "Java",
+		"build.gradle":     "Java",
+		"Gemfile":          "Ruby",
+		"CMakeLists.txt":   "C/C++",
+		"composer.json":    "PHP",
+		"mix.exs":          "Elixir",
+		"DESCRIPTION":      "R",
+		"Package.swift":    "Swift",
+		"build.sbt":        "Scala",
+		"shard.yml":        "Crystal",
+		"rebar.config":     "Erlang",
+		"project.clj":      "Clojure",
+		"pubspec.yaml":     "Dart",
+		"requirements.txt": "Python",
+	} {
+		if _, err := os.Stat(filepath.Join(workspace, file)); err == nil {
+			return lang
+		}
+	}
+	return "unknown"
+}
+
+// scaffoldRepo writes the skeleton files that do not exist yet and returns
+// their paths relative to the workspace.
+func scaffoldRepo(workspace string, a initAnswers) ([]string, error) {
+	dir := filepath.Join(workspace, ".openhands")
+	if err := os.MkdirAll(filepath.Join(dir, "microagents"), 0o755); err != nil {
+		return nil, err
+	}
+
+	var testSection string
+	if a.Test != "" {
+		testSection = fmt.Sprintf("Run the tests with:\n\n```\n%s\n```\n\n", a.Test)
+	}
+	var lintSection string
+	if a.Lint != "" {
+		lintSection = fmt.Sprintf("Run the linter with:\n\n```\n%s\n```\n\n", a.Lint)
+	}
+	files := map[string]string{
+		filepath.Join("microagents", "repo.md"): fmt.Sprintf(`---
+name: repo
+type: repo
+agent: CodeActAgent
+---
+
+This is a %s project.
+
+%s%sDescribe here anything the agent should always know about this
+repository: architecture, conventions, directories to avoid, and how to
+verify changes.
+`, a.Language, testSection, lintSection),
+		"setup.sh": `#!/bin/bash
+# This script runs when the agent session starts, before the first task.
+# Install dependencies or export environment variables your project needs.
+set -e
+`,
+		"cli.toml": fmt.Sprintf(`# Launcher defaults for this repository; see 'openhands --help'.
+schema_version = %d
+
+# tag = "%s"
+# sandbox_image = ""
+# mounts = []
+# budget = 0.0
+
+[llm]
+# model = ""
+`, configSchemaVersion, DefaultTag),
+	}
+
+	var created []string
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), filePerm(rel)); err != nil {
+			return nil, err
+		}
+		created = append(created, filepath.Join(".openhands", rel))
+	}
+	return created, nil
+}
+
+func filePerm(rel string) os.FileMode {
+	if strings.HasSuffix(rel, ".sh") {
+		return 0o755
+	}
+	return 0o644
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+// The -json output shapes are defined here, shared across subcommands so
+// scripts can rely on one stable schema. In JSON mode nothing else may be
+// written to stdout; launcher messages go to stderr.
+
+// printJSON writes v to stdout, indented, with a trailing newline.
+func printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(out))
+	return err
+}
+
+// instanceJSON is the machine-readable form of one managed instance.
+type instanceJSON struct {
+	Name         string    `json:"name"`
+	Workspace    string    `json:"workspace"`
+	Port         int       `json:"port,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	Image        string    `json:"image"`
+	SandboxImage string    `json:"sandbox_image,omitempty"`
+	State        string    `json:"state"`
+	StartedAt    time.Time `json:"started_at"`
+}
+
+// instanceJSONFrom builds the JSON form from a listed container, deriving
+// the sandbox image from the recorded tag label the same way a launch would.
+func instanceJSONFrom(c types.Container) instanceJSON {
+	out := instanceJSON{
+		Name:      containerName(c),
+		Workspace: c.Labels[LabelWorkspace],
+		Image:     c.Image,
+		State:     c.State,
+		StartedAt: time.Unix(c.Created, 0).UTC(),
+	}
+	for _, p := range c.Ports {
+		if p.PublicPort != 0 {
+			out.Port = int(p.PublicPort)
+			break
+		}
+	}
+	if out.Port == 0 {
+		// Host networking publishes no port mappings; the label records the
+		// port the launch chose.
+		if p, err := strconv.Atoi(c.Labels[LabelPort]); err == nil {
+			out.Port = p
+		}
+	}
+	if out.Port != 0 {
+		out.URL = fmt.Sprintf("http://localhost:%d", out.Port)
+	}
+	if tag := c.Labels[LabelVersion]; tag != "" {
+		out.SandboxImage = (&Config{Tag: tag}).RuntimeImage()
+	}
+	return out
+}
+
+// versionJSON is the machine-readable form of `openhands version`.
+type versionJSON struct {
+	Version string `json:"version"`
+	// Latest and Outdated are only present when -check queried for the
+	// newest release.
+	Latest   string `json:"latest,omitempty"`
+	Outdated bool   `json:"outdated,omitempty"`
+}
+
+// statusJSON is the machine-readable form of `openhands status`. Durations
+// are rendered in seconds rather than Go's nanosecond default.
+type statusJSON struct {
+	Conversation string             `json:"conversation"`
+	Events       int                `json:"events"`
+	Iterations   int                `json:"iterations"`
+	UserMessages int                `json:"user_messages"`
+	Errors       int                `json:"errors"`
+	ToolCalls    map[string]int     `json:"tool_calls,omitempty"`
+	StateSeconds map[string]float64 `json:"state_seconds,omitempty"`
+	AgentState   string             `json:"agent_state,omitempty"`
+}
+
+// statusJSONFrom converts computed conversation metrics to the JSON shape.
+func statusJSONFrom(convID string, m conversationMetrics) statusJSON {
+	out := statusJSON{
+		Conversation: convID,
+		Events:       m.Events,
+		Iterations:   m.Iterations,
+		UserMessages: m.UserMsgs,
+		Errors:       m.Errors,
+		AgentState:   m.LastState,
+	}
+	if len(m.ToolCalls) > 0 {
+		out.ToolCalls = m.ToolCalls
+	}
+	if len(m.StateTime) > 0 {
+		out.StateSeconds = make(map[string]float64, len(m.StateTime))
+		for state, d := range m.StateTime {
+			out.StateSeconds[state] = d.Seconds()
+		}
+	}
+	return out
+}
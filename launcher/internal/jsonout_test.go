@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestInstanceJSONFrom(t *testing.T) {
+	c := types.Container{
+		Names:   []string{"/openhands-app"},
+		Image:   DefaultAppImage + ":" + DefaultTag,
+		State:   "running",
+		Created: time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC).Unix(),
+		Labels: map[string]string{
+			LabelWorkspace: "/home/dev/project",
+			LabelVersion:   DefaultTag,
+		},
+		Ports: []types.Port{{PrivatePort: 3000, PublicPort: 3001}},
+	}
+	got := instanceJSONFrom(c)
+	if got.Name != "openhands-app" || got.Workspace != "/home/dev/project" {
+		t.Errorf("name/workspace = %q/%q", got.Name, got.Workspace)
+	}
+	if got.Port != 3001 || got.URL != "http://localhost:3001" {
+		t.Errorf("port/url = %d/%q", got.Port, got.URL)
+	}
+	if got.SandboxImage != (&Config{Tag: DefaultTag}).RuntimeImage() {
+		t.Errorf("sandbox image = %q", got.SandboxImage)
+	}
+	raw, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, field := range []string{`"name"`, `"workspace"`, `"port"`, `"url"`, `"image"`, `"sandbox_image"`, `"state"`, `"started_at"`} {
+		if !strings.Contains(string(raw), field) {
+			t.Errorf("marshaled instance missing %s: %s", field, raw)
+		}
+	}
+}
+
+func TestVersionJSONOmitsLatest(t *testing.T) {
+	raw, err := json.Marshal(versionJSON{Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(raw), "latest") || strings.Contains(string(raw), "outdated") {
+		t.Errorf("version without -check should omit latest/outdated: %s", raw)
+	}
+}
+
+func TestStatusJSONFrom(t *testing.T) {
+	m := conversationMetrics{
+		Events:     10,
+		Iterations: 4,
+		UserMsgs:   2,
+		Errors:     1,
+		ToolCalls:  map[string]int{"run": 3},
+		StateTime:  map[string]time.Duration{"running": 90 * time.Second},
+		LastState:  "running",
+	}
+	got := statusJSONFrom("conv-1", m)
+	if got.StateSeconds["running"] != 90 {
+		t.Errorf("StateSeconds[running] = %v, want 90", got.StateSeconds["running"])
+	}
+	raw, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(raw), `"user_messages":2`) || !strings.Contains(string(raw), `"state_seconds"`) {
+		t.Errorf("unexpected status JSON: %s", raw)
+	}
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/filters"
+)
+
+func TestLaunchLabels(t *testing.T) {
+	cfg := &Config{Workspace: "/home/dev/project", Tag: "0.62", Port: 3100}
+	labels := launchLabels(cfg)
+	want := map[string]string{
+		LabelManaged:   "true",
+		LabelWorkspace: "/home/dev/project",
+		LabelVersion:   "0.62",
+		LabelPort:      "3100",
+	}
+	for k, v := range want {
+		if labels[k] != v {
+			t.Errorf("label %s = %q, want %q", k, labels[k], v)
+		}
+	}
+	cfg.RemoteWorkspace = "/srv/project"
+	if launchLabels(cfg)[LabelWorkspace] != "/srv/project" {
+		t.Error("workspace label should record the remote path for remote launches")
+	}
+}
+
+func TestLaunchLabelPairsSorted(t *testing.T) {
+	cfg := &Config{Workspace: "/w", Tag: "0.62", Port: 3000}
+	pairs := launchLabelPairs(cfg)
+	if len(pairs) != len(launchLabels(cfg)) {
+		t.Fatalf("got %d pairs, want %d", len(pairs), len(launchLabels(cfg)))
+	}
+	for i := 1; i < len(pairs); i++ {
+		if pairs[i-1] >= pairs[i] {
+			t.Errorf("pairs not sorted: %q before %q", pairs[i-1], pairs[i])
+		}
+	}
+}
+
+// The discovery filter must match on the managed label, never on a name
+// prefix, so instances launched with a custom -name stay manageable.
+func TestInstanceDiscoveryFilter(t *testing.T) {
+	f := filters.NewArgs(filters.Arg("label", LabelManaged+"=true"))
+	if got := f.Get("label"); len(got) != 1 || got[0] != "dev.openhands.launcher=true" {
+		t.Errorf("label filter = %v", got)
+	}
+	if len(f.Get("name")) != 0 {
+		t.Error("discovery must not filter by container name")
+	}
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// Launcher is the embeddable launch API. Where Run is the interactive CLI
+// path - wizard, spinner, signal handling, crash restarts - Start performs
+// the same preparation (daemon check, port resolution, image pulls,
+// container creation) but installs no signal handlers, prompts for nothing
+// and writes nothing to the process's terminal, so other programs can
+// launch and manage instances. Output is read through Instance.Output
+// instead of being wired to stdout.
+type Launcher struct {
+	// Config must be validated and merged before Start - FinalizeConfig
+	// on the CLI path, or a hand-built Config with ValidateFlags applied.
+	Config *Config
+}
+
+// Start launches an instance and returns once the container is running;
+// use Instance.WaitReady to block until the server answers. Cancelling ctx
+// after Start returns triggers a graceful stop, the programmatic
+// equivalent of Ctrl-C on the CLI path.
+func (l *Launcher) Start(ctx context.Context) (*Instance, error) {
+	cfg := l.Config
+	if cfg == nil {
+		return nil, fmt.Errorf("launcher: a Config is required")
+	}
+	if err := ValidateFlags(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.LoadSecrets(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ResolveSecretRefs(); err != nil {
+		return nil, err
+	}
+	cli, err := NewDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	inst, err := l.start(ctx, cli, cfg)
+	if err != nil {
+		cli.Close()
+		return nil, err
+	}
+	return inst, nil
+}
+
+func (l *Launcher) start(ctx context.Context, cli *client.Client, cfg *Config) (*Instance, error) {
+	if err := PingDaemon(ctx, cli); err != nil {
+		return nil, err
+	}
+	if cfg.Network != "host" {
+		if err := resolvePort(cfg); err != nil {
+			return nil, err
+		}
+	}
+	refs := []string{cfg.AppImage()}
+	if !cfg.NoDockerSock {
+		refs = append(refs, cfg.RuntimeImage())
+	}
+	for _, ref := range refs {
+		if err := EnsureImagePolicy(ctx, cli, ref, cfg.Pull, cfg.Platform); err != nil {
+			return nil, err
+		}
+	}
+	id, err := CreateContainer(ctx, cli, cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = withRetry(ctx, "start container", func() error {
+		return cli.ContainerStart(ctx, id, types.ContainerStartOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("starting container: %w", err)
+	}
+	inst := &Instance{cli: cli, cfg: cfg, id: id, done: make(chan struct{})}
+	// The wait outlives ctx on purpose: cancellation means "stop the
+	// container gracefully", and Wait still observes the resulting exit.
+	inst.waitCh, inst.errCh = cli.ContainerWait(context.Background(), id, container.WaitConditionNotRunning)
+	go func() {
+		select {
+		case <-ctx.Done():
+			inst.Stop(context.Background(), 15*time.Second)
+		case <-inst.done:
+		}
+	}()
+	return inst, nil
+}
+
+// Instance is a running launch, handed out by Launcher.Start.
+type Instance struct {
+	cli    *client.Client
+	cfg    *Config
+	id     string
+	waitCh <-chan container.WaitResponse
+	errCh  <-chan error
+	done   chan struct{}
+	once   sync.Once
+}
+
+// ID returns the container ID.
+func (i *Instance) ID() string { return i.id }
+
+// URL returns where the UI is reachable.
+func (i *Instance) URL() string { return i.cfg.uiURL() }
+
+// WaitReady blocks until the server answers at URL, using the same
+// readiness probing as the CLI path.
+func (i *Instance) WaitReady(ctx context.Context) error {
+	return waitReady(ctx, i.cli, i.id, i.cfg.uiURL(), i.cfg.OpenPath, i.cfg.StartupTimeout)
+}
+
+// Wait blocks until the container exits. A nonzero exit surfaces as an
+// *exitCodeError, so ExitCode maps it like the CLI does.
+func (i *Instance) Wait() error {
+	select {
+	case err := <-i.errCh:
+		return fmt.Errorf("waiting for container: %w", err)
+	case status := <-i.waitCh:
+		if status.Error != nil {
+			return fmt.Errorf("container exited with error: %s", status.Error.Message)
+		}
+		if status.StatusCode != 0 {
+			return &exitCodeError{
+				code: int(status.StatusCode),
+				msg:  fmt.Sprintf("container exited with status %d", status.StatusCode),
+			}
+		}
+		return nil
+	}
+}
+
+// Stop asks the container to shut down, escalating to kill after grace.
+// It is safe to call more than once; later calls are no-ops.
+func (i *Instance) Stop(ctx context.Context, grace time.Duration) error {
+	var err error
+	i.once.Do(func() {
+		seconds := int(grace / time.Second)
+		err = i.cli.ContainerStop(ctx, i.id, container.StopOptions{Timeout: &seconds})
+	})
+	return err
+}
+
+// Output returns the container's combined output stream, following new
+// output until ctx is cancelled or the container exits. The caller closes
+// it.
+func (i *Instance) Output(ctx context.Context) (io.ReadCloser, error) {
+	return ContainerLogsFollow(ctx, i.cli, i.id, "all")
+}
+
+// Close releases the client connection. It does not stop the container;
+// call Stop first for that.
+func (i *Instance) Close() error {
+	close(i.done)
+	return i.cli.Close()
+}
@@ -0,0 +1,20 @@
+package internal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLauncherStartRequiresConfig(t *testing.T) {
+	l := &Launcher{}
+	if _, err := l.Start(context.Background()); err == nil {
+		t.Fatal("Start with a nil Config should fail")
+	}
+}
+
+func TestInstanceURL(t *testing.T) {
+	inst := &Instance{cfg: &Config{Port: 3311}}
+	if got := inst.URL(); got != "http://localhost:3311" {
+		t.Errorf("URL() = %q", got)
+	}
+}
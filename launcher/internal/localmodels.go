@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// localModelServer is one detected local inference server.
+type localModelServer struct {
+	Name string // "Ollama", "LM Studio", "vLLM"
+	// BaseURL is the endpoint as seen from inside the app container; local
+	// servers listen on the host, so it goes through host.docker.internal.
+	BaseURL string
+	// Prefix is the LiteLLM provider prefix for models served here.
+	Prefix string
+	Models []string
+}
+
+// localModelProbe describes where a known server usually listens and how to
+// list its models.
+type localModelProbe struct {
+	name    string
+	hostURL string // probe target on the host
+	baseURL string // same endpoint from inside the container
+	prefix  string
+	list    func(base string) ([]string, error)
+}
+
+var localModelProbes = []localModelProbe{
+	{"Ollama", "http://localhost:11434", "http://host.docker.internal:11434", "ollama/", listOllamaModels},
+	{"LM Studio", "http://localhost:1234/v1", "http://host.docker.internal:1234/v1", "openai/", listOpenAIModels},
+	{"vLLM", "http://localhost:8000/v1", "http://host.docker.internal:8000/v1", "openai/", listOpenAIModels},
+}
+
+// detectLocalModels probes the default ports of Ollama, LM Studio and vLLM
+// and returns whatever is running with its available models. Probes are
+// quick (1s timeout) so the caller can run this inline in a wizard.
+func detectLocalModels() []localModelServer {
+	var found []localModelServer
+	for _, p := range localModelProbes {
+		models, err := p.list(p.hostURL)
+		if err != nil || len(models) == 0 {
+			continue
+		}
+		found = append(found, localModelServer{
+			Name:    p.name,
+			BaseURL: p.baseURL,
+			Prefix:  p.prefix,
+			Models:  models,
+		})
+	}
+	return found
+}
+
+var localProbeClient = &http.Client{Timeout: time.Second}
+
+// listOllamaModels queries Ollama's native tags endpoint.
+func listOllamaModels(base string) ([]string, error) {
+	raw, err := probeGet(base + "/api/tags")
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(doc.Models))
+	for _, m := range doc.Models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
+// listOpenAIModels queries an OpenAI-compatible /models endpoint (LM Studio,
+// vLLM).
+func listOpenAIModels(base string) ([]string, error) {
+	raw, err := probeGet(base + "/models")
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(doc.Data))
+	for _, m := range doc.Data {
+		names = append(names, m.ID)
+	}
+	return names, nil
+}
+
+func probeGet(url string) ([]byte, error) {
+	resp, err := localProbeClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// reportLocalModels prints detected local servers with the config lines that
+// would use them, zero API key required.
+func reportLocalModels(w io.Writer, servers []localModelServer) {
+	if len(servers) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Local inference servers detected (no API key needed):")
+	for _, s := range servers {
+		models := s.Models
+		if len(models) > 3 {
+			models = append(append([]string{}, models[:3]...), fmt.Sprintf("(+%d more)", len(s.Models)-3))
+		}
+		fmt.Fprintf(w, "  %s at %s: %s\n", s.Name, s.BaseURL, strings.Join(models, ", "))
+		fmt.Fprintf(w, "    use with: [llm] model = %q, base_url = %q\n", s.Prefix+s.Models[0], s.BaseURL)
+	}
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// defaultLogRetention is how many launch logs are kept before the oldest
+// are deleted at startup.
+const defaultLogRetention = 10
+
+// logDir returns the directory launch logs are written to.
+func logDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// openLaunchLog opens this launch's log file and writes its header, so a
+// failed startup leaves a single file that can be attached to a bug report.
+// Logging is best-effort: any failure warns and returns nil, never blocking
+// the launch.
+func openLaunchLog(cfg *Config) *os.File {
+	if cfg.NoLogFile {
+		return nil
+	}
+	path := cfg.LogFile
+	if path == "" {
+		dir, err := logDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: launch log disabled: %v\n", err)
+			return nil
+		}
+		pruneLogs(dir, cfg.LogRetention)
+		path = filepath.Join(dir, time.Now().Format("cli-20060102-150405.log"))
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: launch log disabled: %v\n", err)
+		return nil
+	}
+	writeLogHeader(f, cfg)
+	return f
+}
+
+// writeLogHeader records the version, the resolved configuration and the
+// composed docker command - with secrets masked - so one file answers most
+// "what exactly did it run?" questions.
+func writeLogHeader(w io.Writer, cfg *Config) {
+	fmt.Fprintf(w, "openhands %s, launched %s\n", Version, time.Now().Format(time.RFC3339))
+	fmt.Fprintf(w, "workspace: %s\n", cfg.Workspace)
+	fmt.Fprintf(w, "app image: %s\n", cfg.AppImage())
+	fmt.Fprintf(w, "runtime image: %s\n", cfg.RuntimeImage())
+	fmt.Fprintf(w, "url: %s\n", cfg.uiURL())
+	fmt.Fprintf(w, "command: %s\n", renderDockerCommand(cfg, false))
+	fmt.Fprintln(w, "--- container output ---")
+}
+
+// pruneLogs deletes the oldest launch logs, keeping the newest `keep`. The
+// timestamped names sort chronologically, so name order is age order.
+func pruneLogs(dir string, keep int) {
+	if keep <= 0 {
+		keep = defaultLogRetention
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "cli-*.log"))
+	if err != nil || len(matches) < keep {
+		return
+	}
+	sort.Strings(matches)
+	for _, path := range matches[:len(matches)-keep+1] {
+		os.Remove(path)
+	}
+}
+
+// latestLogPath returns the newest launch log, or "" when there is none;
+// doctor points bug reports at it.
+func latestLogPath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "logs", "cli-*.log"))
+	if err != nil || len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches)
+	return matches[len(matches)-1]
+}
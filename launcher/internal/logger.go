@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+)
+
+// logLevel orders the launcher's own message levels. All leveled output goes
+// to stderr, keeping stdout for content scripts may want to parse.
+type launcherLogLevel int
+
+const (
+	levelDebug launcherLogLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// currentLogLevel is the minimum level that gets printed: info by default,
+// debug under -verbose, warn under -quiet.
+var currentLogLevel = levelInfo
+
+// setLogLevel applies the -verbose/-quiet flags.
+func setLogLevel(verbose, quiet bool) {
+	switch {
+	case verbose:
+		currentLogLevel = levelDebug
+	case quiet:
+		currentLogLevel = levelWarn
+	default:
+		currentLogLevel = levelInfo
+	}
+}
+
+// quietMode reports whether -quiet is in effect, for the few places whose
+// output changes shape rather than just being suppressed.
+func quietMode() bool {
+	return currentLogLevel >= levelWarn
+}
+
+func logf(lv launcherLogLevel, format string, args ...interface{}) {
+	if lv < currentLogLevel {
+		return
+	}
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+func debugf(format string, args ...interface{}) { logf(levelDebug, format, args...) }
+func infof(format string, args ...interface{})  { logf(levelInfo, format, args...) }
+func warnf(format string, args ...interface{})  { logf(levelWarn, format, args...) }
+func errorf(format string, args ...interface{}) { logf(levelError, format, args...) }
@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// CmdLogs implements `openhands logs [NAME]`: it prints (and with -f
+// follows) a container's combined log stream with client-side filtering,
+// because raw LOG_ALL_EVENTS output is far too noisy when chasing one error.
+// It is the way to see what a -d instance, or one whose terminal is gone, is
+// doing. Without a NAME the single running instance is picked automatically;
+// -runtime switches to the sandbox runtime container's logs instead of the
+// app's.
+func CmdLogs(args []string) int {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	follow := fs.Bool("f", false, "keep following new output")
+	tail := fs.String("tail", "all", "number of trailing lines to start from")
+	since := fs.String("since", "", "only show output newer than this, e.g. 10m or an RFC3339 time")
+	grep := fs.String("grep", "", "only show lines matching this regular expression")
+	level := fs.String("level", "", "minimum log level (debug, info, warning, error)")
+	component := fs.String("component", "", "only show lines from this component, e.g. runtime or agent")
+	runtimeLogs := fs.Bool("runtime", false, "show the sandbox runtime container's logs instead of the app's")
+	contextName := fs.String("context", "", "docker CLI context the instance runs on")
+	fs.Parse(args)
+	if err := applyContextFlag(*contextName); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	filter, err := newLogFilter(*grep, *level, *component)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 2
+	}
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	target, err := resolveLogTarget(ctx, cli, fs.Arg(0), *runtimeLogs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	rc, err := cli.ContainerLogs(ctx, target, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     *follow,
+		Tail:       *tail,
+		Since:      *since,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if filter.Match(line) {
+			fmt.Println(line)
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "openhands: reading logs: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// resolveLogTarget picks the container whose logs to show. The name argument
+// matches a managed instance; empty means the single running one. With
+// runtime set, the newest openhands-runtime-* container is used instead -
+// the server names sandbox containers that way per conversation.
+func resolveLogTarget(ctx context.Context, cli *client.Client, name string, runtime bool) (string, error) {
+	if runtime {
+		containers, err := cli.ContainerList(ctx, types.ContainerListOptions{})
+		if err != nil {
+			return "", err
+		}
+		var newest string
+		var created int64
+		for _, c := range containers {
+			if strings.HasPrefix(containerName(c), "openhands-runtime-") && c.Created >= created {
+				newest, created = c.ID, c.Created
+			}
+		}
+		if newest == "" {
+			return "", fmt.Errorf("no sandbox runtime container is running (the server starts one per conversation)")
+		}
+		return newest, nil
+	}
+	instances, err := listInstances(ctx, cli)
+	if err != nil {
+		return "", err
+	}
+	if name != "" {
+		for _, c := range instances {
+			if containerName(c) == name || strings.HasPrefix(c.ID, name) {
+				return c.ID, nil
+			}
+		}
+		return "", fmt.Errorf("no running instance named %q (see `openhands ps`)", name)
+	}
+	switch len(instances) {
+	case 0:
+		return "", fmt.Errorf("no running instances; start one first or name a stopped container explicitly")
+	case 1:
+		return instances[0].ID, nil
+	default:
+		return "", fmt.Errorf("several instances are running; name one (see `openhands ps`)")
+	}
+}
+
+// logLevels orders the levels the backend emits.
+var logLevels = map[string]int{
+	"DEBUG":    0,
+	"INFO":     1,
+	"WARNING":  2,
+	"ERROR":    3,
+	"CRITICAL": 4,
+}
+
+var levelRe = regexp.MustCompile(`\b(DEBUG|INFO|WARNING|ERROR|CRITICAL)\b`)
+
+// logFilter decides which log lines to show. Lines without a recognizable
+// level (tracebacks, continuation lines) inherit the decision made for the
+// previous levelled line, so stack traces stay attached to their error.
+type logFilter struct {
+	grep      *regexp.Regexp
+	minLevel  int
+	component string
+
+	lastLevelled bool
+}
+
+func newLogFilter(grep, level, component string) (*logFilter, error) {
+	f := &logFilter{minLevel: -1, component: strings.ToLower(component), lastLevelled: true}
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid -grep pattern: %w", err)
+		}
+		f.grep = re
+	}
+	if level != "" {
+		lv, ok := logLevels[strings.ToUpper(level)]
+		if !ok {
+			return nil, fmt.Errorf("unknown -level %q (expected debug, info, warning or error)", level)
+		}
+		f.minLevel = lv
+	}
+	return f, nil
+}
+
+// Match reports whether the line passes all filters.
+func (f *logFilter) Match(line string) bool {
+	m := levelRe.FindString(line)
+	if m == "" && f.grep == nil {
+		// Continuation line: follow the previous decision for level and
+		// component filtering.
+		return f.lastLevelled
+	}
+	ok := true
+	if f.minLevel >= 0 {
+		if m == "" {
+			ok = f.lastLevelled
+		} else {
+			ok = logLevels[m] >= f.minLevel
+		}
+	}
+	if ok && f.component != "" {
+		ok = matchComponent(line, f.component)
+	}
+	if m != "" {
+		f.lastLevelled = ok
+	}
+	if ok && f.grep != nil {
+		ok = f.grep.MatchString(line)
+	}
+	return ok
+}
+
+// matchComponent checks the logger prefix of a line (everything before the
+// message separator) for the component name, so grepping for "runtime" does
+// not also match messages that merely mention the word.
+func matchComponent(line, component string) bool {
+	head := line
+	if i := strings.Index(line, " - "); i >= 0 {
+		head = line[:i+3]
+		if j := strings.Index(line[i+3:], " - "); j >= 0 {
+			head = line[:i+3+j]
+		}
+	}
+	return strings.Contains(strings.ToLower(head), component)
+}
@@ -0,0 +1,51 @@
+package internal
+
+import "testing"
+
+func TestLogFilterLevel(t *testing.T) {
+	f, err := newLogFilter("", "warning", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Match("12:00:01 - openhands:INFO: session.py:10 - starting") {
+		t.Error("INFO line should be filtered at -level warning")
+	}
+	if !f.Match("12:00:02 - openhands:ERROR: runtime.py:99 - boom") {
+		t.Error("ERROR line should pass at -level warning")
+	}
+	// Continuation lines follow the preceding levelled line.
+	if !f.Match("Traceback (most recent call last):") {
+		t.Error("traceback after an ERROR line should pass")
+	}
+	if f.Match("12:00:03 - openhands:DEBUG: runtime.py:100 - retrying") {
+		t.Error("DEBUG line should be filtered")
+	}
+	if f.Match("  File \"runtime.py\", line 100") {
+		t.Error("continuation after a filtered line should be filtered too")
+	}
+}
+
+func TestLogFilterComponentAndGrep(t *testing.T) {
+	f, err := newLogFilter("timeout", "", "runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Match("12:00:01 - openhands.runtime:ERROR: client.py:1 - connect timeout") {
+		t.Error("matching component and grep should pass")
+	}
+	if f.Match("12:00:02 - openhands.runtime:ERROR: client.py:2 - connection refused") {
+		t.Error("grep mismatch should be filtered")
+	}
+	if f.Match("12:00:03 - openhands.agent:ERROR: agent.py:3 - runtime timeout mentioned") {
+		t.Error("component must match the logger prefix, not the message")
+	}
+}
+
+func TestNewLogFilterRejectsBadInput(t *testing.T) {
+	if _, err := newLogFilter("(", "", ""); err == nil {
+		t.Error("expected an error for an invalid regexp")
+	}
+	if _, err := newLogFilter("", "loud", ""); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// parseMountSpec normalizes one -mount SRC:DST[:ro|rw] spec: ~ is expanded
+// against home, a relative source is resolved against cwd, and the
+// destination must be an absolute in-container path. The returned spec
+// always carries an explicit mode, which makes normalization idempotent for
+// the re-validation after config merging.
+func parseMountSpec(spec, home, cwd string) (string, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return "", fmt.Errorf("invalid -mount %q: expected SRC:DST[:ro|rw]", spec)
+	}
+	src, dst := parts[0], parts[1]
+	if src == "" || dst == "" {
+		return "", fmt.Errorf("invalid -mount %q: source and destination are required", spec)
+	}
+	mode := "rw"
+	if len(parts) == 3 {
+		mode = parts[2]
+		if mode != "ro" && mode != "rw" {
+			return "", fmt.Errorf("invalid -mount %q: mode must be ro or rw, not %q", spec, mode)
+		}
+	}
+	if src == "~" || strings.HasPrefix(src, "~/") {
+		src = filepath.Join(home, strings.TrimPrefix(src, "~"))
+	}
+	if !filepath.IsAbs(src) {
+		src = filepath.Join(cwd, src)
+	}
+	src = filepath.Clean(src)
+	if !filepath.IsAbs(dst) {
+		return "", fmt.Errorf("invalid -mount %q: destination must be an absolute path inside the container", spec)
+	}
+	return src + ":" + filepath.Clean(dst) + ":" + mode, nil
+}
+
+// mountDest returns the in-container destination of a normalized mount spec.
+func mountDest(spec string) string {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// mountSource returns the host source of a normalized mount spec.
+func mountSource(spec string) string {
+	src, _, _ := strings.Cut(spec, ":")
+	return src
+}
@@ -0,0 +1,43 @@
+package internal
+
+import "testing"
+
+func TestParseMountSpec(t *testing.T) {
+	const home, cwd = "/home/dev", "/home/dev/project"
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"/data:/data", "/data:/data:rw"},
+		{"/data:/data:ro", "/data:/data:ro"},
+		{"~/lib:/opt/lib", "/home/dev/lib:/opt/lib:rw"},
+		{"../shared:/shared:ro", "/home/dev/shared:/shared:ro"},
+		{"./vendor/:/vendor", "/home/dev/project/vendor:/vendor:rw"},
+		// Normalized specs must survive a second pass unchanged.
+		{"/home/dev/lib:/opt/lib:rw", "/home/dev/lib:/opt/lib:rw"},
+	}
+	for _, tt := range tests {
+		got, err := parseMountSpec(tt.spec, home, cwd)
+		if err != nil {
+			t.Errorf("parseMountSpec(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseMountSpec(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseMountSpecErrors(t *testing.T) {
+	for _, spec := range []string{
+		"/data",
+		"/data:relative/dst",
+		"/data:/data:rx",
+		"/a:/b:ro:extra",
+		":/data",
+	} {
+		if _, err := parseMountSpec(spec, "/home/dev", "/home/dev"); err == nil {
+			t.Errorf("parseMountSpec(%q): expected an error", spec)
+		}
+	}
+}
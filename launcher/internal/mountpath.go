@@ -0,0 +1,26 @@
+package internal
+
+import "strings"
+
+// drivePathToPosix converts a Windows drive path to the POSIX form Docker
+// Desktop accepts in bind specs: C:\Users\me\proj becomes /c/Users/me/proj.
+// A colon inside the host path would otherwise collide with the host:dest
+// separator of -v. Paths without a drive prefix come back unchanged.
+func drivePathToPosix(p string) string {
+	if len(p) < 2 || p[1] != ':' {
+		return p
+	}
+	drive := p[0]
+	switch {
+	case drive >= 'A' && drive <= 'Z':
+		drive += 'a' - 'A'
+	case drive >= 'a' && drive <= 'z':
+	default:
+		return p
+	}
+	rest := strings.ReplaceAll(p[2:], `\`, "/")
+	if !strings.HasPrefix(rest, "/") {
+		rest = "/" + rest
+	}
+	return "/" + string(drive) + rest
+}
@@ -0,0 +1,19 @@
+package internal
+
+import "testing"
+
+func TestDrivePathToPosix(t *testing.T) {
+	cases := map[string]string{
+		`C:\Users\me\proj`: "/c/Users/me/proj",
+		`D:\`:              "/d/",
+		`c:\work`:          "/c/work",
+		"/home/me/proj":    "/home/me/proj",
+		`\\server\share`:   `\\server\share`, // UNC paths pass through untouched
+		"":                 "",
+	}
+	for in, want := range cases {
+		if got := drivePathToPosix(in); got != want {
+			t.Errorf("drivePathToPosix(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
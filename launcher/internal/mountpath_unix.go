@@ -0,0 +1,9 @@
+//go:build !windows
+
+package internal
+
+// hostMountPath renders a host path for the host side of a bind spec. Unix
+// paths are already what the engine expects.
+func hostMountPath(p string) string {
+	return p
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package internal
+
+// hostMountPath renders a host path for the host side of a bind spec. Drive
+// paths are rewritten to the /c/... form so the colon after the drive letter
+// does not split the host:dest spec.
+func hostMountPath(p string) string {
+	return drivePathToPosix(p)
+}
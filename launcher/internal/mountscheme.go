@@ -0,0 +1,51 @@
+package internal
+
+import "strings"
+
+// sandboxVolumesMinVersion is the first app release that configures
+// workspace mounting through SANDBOX_VOLUMES. Older servers only understand
+// WORKSPACE_MOUNT_PATH plus the fixed /opt/workspace_base bind, and newer
+// ones ignore that pair - each scheme is invisible to the other side, so
+// picking the wrong one means the agent sees an empty workspace.
+const sandboxVolumesMinVersion = "0.40"
+
+// useSandboxVolumes decides which mounting scheme the app image expects,
+// from its tag. Custom tags that carry no parseable version (nightly, main,
+// a CI build) are assumed current; -legacy-mounts is the explicit override
+// for such tags built from an old tree, and also forces the old scheme for
+// versioned tags when needed.
+func useSandboxVolumes(cfg *Config) bool {
+	if cfg.LegacyMounts {
+		return false
+	}
+	if len(parseVersion(cfg.Tag)) == 0 {
+		return true
+	}
+	return compareVersions(cfg.Tag, sandboxVolumesMinVersion) >= 0
+}
+
+// sandboxVolumesSpec renders the SANDBOX_VOLUMES value: the workspace plus
+// any -mount extras, comma-separated in docker's src:dst:mode syntax.
+func sandboxVolumesSpec(cfg *Config) string {
+	volumes := []string{cfg.mountWorkspace() + ":" + SandboxWorkspacePath + ":" + cfg.workspaceMode()}
+	volumes = append(volumes, cfg.ExtraMounts...)
+	return strings.Join(volumes, ",")
+}
+
+// workspaceMode is the mount mode of the workspace bind, "ro" under
+// -read-only.
+func (c *Config) workspaceMode() string {
+	if c.ReadOnly {
+		return "ro"
+	}
+	return "rw"
+}
+
+// workspaceBindSuffix is workspaceMode as a bind-spec suffix; the read-write
+// default stays implicit, matching what docker run renders.
+func (c *Config) workspaceBindSuffix() string {
+	if c.ReadOnly {
+		return ":ro"
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUseSandboxVolumes(t *testing.T) {
+	tests := []struct {
+		tag    string
+		legacy bool
+		want   bool
+	}{
+		{"0.62", false, true},
+		{"0.40", false, true},
+		{"0.39", false, false},
+		{"0.12", false, false},
+		{"main", false, true},
+		{"nightly", false, true},
+		{"0.62", true, false},
+		{"main", true, false},
+	}
+	for _, tt := range tests {
+		cfg := &Config{Tag: tt.tag, LegacyMounts: tt.legacy}
+		if got := useSandboxVolumes(cfg); got != tt.want {
+			t.Errorf("useSandboxVolumes(tag=%q, legacy=%v) = %v, want %v", tt.tag, tt.legacy, got, tt.want)
+		}
+	}
+}
+
+func TestContainerEnvMountSchemes(t *testing.T) {
+	cfg := &Config{Tag: "0.62", Workspace: "/home/dev/project", ExtraMounts: []string{"/data:/data:ro"}}
+	env := strings.Join(containerEnv(cfg), "\n")
+	if !strings.Contains(env, "SANDBOX_VOLUMES=/home/dev/project:/workspace:rw,/data:/data:ro") {
+		t.Errorf("modern scheme should mount via SANDBOX_VOLUMES, got:\n%s", env)
+	}
+	if strings.Contains(env, "WORKSPACE_MOUNT_PATH=") {
+		t.Errorf("modern scheme must not set WORKSPACE_MOUNT_PATH, got:\n%s", env)
+	}
+
+	cfg.LegacyMounts = true
+	env = strings.Join(containerEnv(cfg), "\n")
+	if !strings.Contains(env, "WORKSPACE_MOUNT_PATH=/home/dev/project") {
+		t.Errorf("legacy scheme should set WORKSPACE_MOUNT_PATH, got:\n%s", env)
+	}
+	if !strings.Contains(env, "SANDBOX_VOLUMES=/home/dev/project:/opt/workspace_base:rw,/data:/data:ro") {
+		t.Errorf("legacy scheme with extras should still pass SANDBOX_VOLUMES, got:\n%s", env)
+	}
+}
+
+func TestBuildDockerArgsMountSchemes(t *testing.T) {
+	cfg := &Config{Tag: "0.62", Workspace: "/home/dev/project", Bind: "127.0.0.1", Port: 3000}
+	argv := strings.Join(buildDockerArgs(cfg, false), " ")
+	if strings.Contains(argv, "/home/dev/project:"+WorkspaceMountPath) {
+		t.Errorf("modern scheme must not bind the workspace into the app container, got:\n%s", argv)
+	}
+
+	cfg.Tag = "0.39"
+	argv = strings.Join(buildDockerArgs(cfg, false), " ")
+	if !strings.Contains(argv, "/home/dev/project:"+WorkspaceMountPath) {
+		t.Errorf("legacy scheme should bind the workspace into the app container, got:\n%s", argv)
+	}
+}
+
+func TestReadOnlyWorkspace(t *testing.T) {
+	cfg := &Config{Tag: "0.62", Workspace: "/home/dev/project", Bind: "127.0.0.1", Port: 3000, ReadOnly: true}
+	if got := sandboxVolumesSpec(cfg); !strings.HasPrefix(got, "/home/dev/project:/workspace:ro") {
+		t.Errorf("read-only sandbox volume spec = %q", got)
+	}
+
+	cfg.LegacyMounts = true
+	argv := strings.Join(buildDockerArgs(cfg, false), " ")
+	if !strings.Contains(argv, "/home/dev/project:"+WorkspaceMountPath+":ro") {
+		t.Errorf("read-only legacy bind missing :ro, got:\n%s", argv)
+	}
+
+	cfg.ReadOnly = false
+	if got := sandboxVolumesSpec(cfg); !strings.HasPrefix(got, "/home/dev/project:/workspace:rw") {
+		t.Errorf("default sandbox volume spec = %q", got)
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUIURL(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{name: "default", cfg: Config{Bind: "127.0.0.1", Port: 3000}, want: "http://127.0.0.1:3000"},
+		{name: "wildcard bind", cfg: Config{Bind: "0.0.0.0", Port: 4000}, want: "http://localhost:4000"},
+		{name: "host network ignores publish settings", cfg: Config{Bind: "127.0.0.1", Port: 3000, Network: "host"}, want: "http://localhost:3000"},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.uiURL(); got != tt.want {
+			t.Errorf("%s: uiURL() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildDockerArgsNetwork(t *testing.T) {
+	base := Config{Workspace: "/ws", Tag: DefaultTag, Port: 3000, Bind: "127.0.0.1"}
+
+	named := base
+	named.Network = "llmnet"
+	args := strings.Join(buildDockerArgs(&named, false), " ")
+	if !strings.Contains(args, "--network llmnet") {
+		t.Errorf("named network: missing --network llmnet in %q", args)
+	}
+	if !strings.Contains(args, "-p 127.0.0.1:3000:3000") {
+		t.Errorf("named network: publish mapping should remain in %q", args)
+	}
+
+	host := base
+	host.Network = "host"
+	args = strings.Join(buildDockerArgs(&host, false), " ")
+	if !strings.Contains(args, "--network host") {
+		t.Errorf("host network: missing --network host in %q", args)
+	}
+	if strings.Contains(args, "-p ") || strings.Contains(args, "--add-host") {
+		t.Errorf("host network: publish or add-host must be dropped in %q", args)
+	}
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+)
+
+// watchResourcePressure follows Docker events for the given container and
+// turns OOM kills into an explicit explanation - which limit was hit and how
+// to raise it - instead of the generic disconnect users otherwise see. It
+// also samples memory usage and warns once on sustained pressure so the OOM
+// is not a surprise. It returns when ctx is cancelled.
+func watchResourcePressure(ctx context.Context, cli *client.Client, id string) {
+	f := filters.NewArgs(
+		filters.Arg("type", "container"),
+		filters.Arg("container", id),
+		filters.Arg("event", "oom"),
+	)
+	msgCh, errCh := cli.Events(ctx, types.EventsOptions{Filters: f})
+	go sampleMemoryPressure(ctx, cli, id)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-errCh:
+			return
+		case <-msgCh:
+			limit := memoryLimit(ctx, cli, id)
+			fmt.Fprintf(os.Stderr, "\nopenhands: the container was OOM-killed%s.\n", limitPhrase(limit))
+			fmt.Fprintln(os.Stderr, "Raise the limit (docker update --memory, or give the Docker VM more memory on Mac/Windows) and relaunch; large builds and browser use are the usual culprits.")
+		}
+	}
+}
+
+// pressureThreshold is the usage fraction above which we warn.
+const pressureThreshold = 0.9
+
+// sampleMemoryPressure polls container stats and warns once when usage stays
+// above the threshold for consecutive samples.
+func sampleMemoryPressure(ctx context.Context, cli *client.Client, id string) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	high := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		usage, limit, ok := memoryUsage(ctx, cli, id)
+		if !ok || limit == 0 {
+			continue
+		}
+		if float64(usage)/float64(limit) >= pressureThreshold {
+			high++
+		} else {
+			high = 0
+		}
+		if high == 2 {
+			fmt.Fprintf(os.Stderr, "\nopenhands: memory usage is at %s of the %s limit; the container is close to being OOM-killed.\n",
+				units.BytesSize(float64(usage)), units.BytesSize(float64(limit)))
+			return
+		}
+	}
+}
+
+// memoryUsage reads one stats sample.
+func memoryUsage(ctx context.Context, cli *client.Client, id string) (usage, limit uint64, ok bool) {
+	resp, err := cli.ContainerStatsOneShot(ctx, id)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer resp.Body.Close()
+	var stats types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return 0, 0, false
+	}
+	return stats.MemoryStats.Usage, stats.MemoryStats.Limit, true
+}
+
+// memoryLimit returns the container's configured memory limit in bytes, or 0
+// when unlimited.
+func memoryLimit(ctx context.Context, cli *client.Client, id string) int64 {
+	insp, err := cli.ContainerInspect(ctx, id)
+	if err != nil || insp.HostConfig == nil {
+		return 0
+	}
+	return insp.HostConfig.Memory
+}
+
+func limitPhrase(limit int64) string {
+	if limit <= 0 {
+		return " (no explicit limit was set, so the Docker VM itself ran out of memory)"
+	}
+	return fmt.Sprintf(" after hitting its %s memory limit", units.BytesSize(float64(limit)))
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// hostPlatform is the container platform this machine runs natively.
+// Containers are always linux, so only the architecture comes from the host;
+// on Apple Silicon this is linux/arm64 even though the host OS is darwin.
+func hostPlatform() string {
+	return "linux/" + runtime.GOARCH
+}
+
+// parsePlatform validates a -platform value and splits it into an OCI
+// platform for the engine API. Only linux targets make sense for these
+// images.
+func parsePlatform(spec string) (*ocispec.Platform, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	os, arch, ok := strings.Cut(spec, "/")
+	if !ok || os == "" || arch == "" {
+		return nil, fmt.Errorf("invalid -platform %q: expected os/arch, e.g. linux/amd64", spec)
+	}
+	if os != "linux" {
+		return nil, fmt.Errorf("invalid -platform %q: the images only exist for linux targets", spec)
+	}
+	return &ocispec.Platform{OS: os, Architecture: arch}, nil
+}
+
+// imagePlatform reports the os/arch of a locally present image, or "" when
+// the image is absent or the engine cannot say.
+func imagePlatform(ctx context.Context, cli *client.Client, ref string) (string, error) {
+	insp, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if insp.Os == "" || insp.Architecture == "" {
+		return "", nil
+	}
+	return insp.Os + "/" + insp.Architecture, nil
+}
+
+// platformWarnings compares each image's platform against the host's native
+// one and describes every mismatch. An emulated image works, but qemu makes
+// the sandbox crawl, and nothing else in the stack says why - so this is
+// loud. The lookup is injected so the check is testable without a daemon.
+func platformWarnings(native string, lookup func(ref string) (string, error), refs ...string) []string {
+	var warnings []string
+	for _, ref := range refs {
+		got, err := lookup(ref)
+		if err != nil || got == "" || got == native {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"image %s is %s but this machine is %s; it will run under emulation and be very slow. Use a tag published for %s, or silence this with -platform %s if emulation is intended.",
+			ref, got, native, native, got))
+	}
+	return warnings
+}
+
+// warnImagePlatforms runs the mismatch check against the local images once
+// they are ensured present. An explicit -platform means the user chose
+// emulation knowingly, so the check is skipped.
+func warnImagePlatforms(ctx context.Context, cli *client.Client, cfg *Config) {
+	if cfg.Platform != "" {
+		return
+	}
+	lookup := func(ref string) (string, error) { return imagePlatform(ctx, cli, ref) }
+	refs := []string{cfg.AppImage()}
+	if !cfg.NoDockerSock {
+		refs = append(refs, cfg.RuntimeImage())
+	}
+	for _, w := range platformWarnings(hostPlatform(), lookup, refs...) {
+		warnf("%s", w)
+	}
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParsePlatform(t *testing.T) {
+	p, err := parsePlatform("linux/arm64")
+	if err != nil || p == nil || p.OS != "linux" || p.Architecture != "arm64" {
+		t.Errorf("parsePlatform(linux/arm64) = %+v, %v", p, err)
+	}
+	if p, err := parsePlatform(""); err != nil || p != nil {
+		t.Errorf("parsePlatform(\"\") = %+v, %v, want nil, nil", p, err)
+	}
+	for _, bad := range []string{"amd64", "windows/amd64", "linux/", "/arm64"} {
+		if _, err := parsePlatform(bad); err == nil {
+			t.Errorf("parsePlatform(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPlatformWarnings(t *testing.T) {
+	lookup := func(ref string) (string, error) {
+		switch ref {
+		case "app:latest":
+			return "linux/amd64", nil
+		case "runtime:latest":
+			return "linux/arm64", nil
+		case "absent:latest":
+			return "", errors.New("not found")
+		}
+		return "", nil
+	}
+	got := platformWarnings("linux/arm64", lookup, "app:latest", "runtime:latest", "absent:latest")
+	if len(got) != 1 {
+		t.Fatalf("platformWarnings = %d warnings, want 1: %v", len(got), got)
+	}
+	if !strings.Contains(got[0], "app:latest") || !strings.Contains(got[0], "emulation") {
+		t.Errorf("warning should name the image and mention emulation: %q", got[0])
+	}
+	if got := platformWarnings("linux/amd64", lookup, "app:latest"); got != nil {
+		t.Errorf("matching platform produced warnings: %v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// LabelPool marks runtime containers kept warm by `openhands pool`.
+const LabelPool = "dev.openhands.pool"
+
+// poolInterval is how often the pool daemon reconciles.
+const poolInterval = 15 * time.Second
+
+// CmdPool implements `openhands pool`: a daemon that keeps N runtime
+// containers warm for the given image tag. Starting a runtime container cold
+// costs a pull plus one to two minutes of initialization; a warm container
+// has the image layers pulled and its filesystem, interpreter and package
+// caches hot, so new conversations that land on the same image start nearly
+// instantly. The daemon reconciles the pool every few seconds and tears it
+// down on SIGINT/SIGTERM.
+func CmdPool(args []string) int {
+	cfg := &Config{}
+	fs := flag.NewFlagSet("pool", flag.ExitOnError)
+	size := fs.Int("size", 2, "number of runtime containers to keep warm")
+	fs.StringVar(&cfg.Tag, "tag", DefaultTag, "app image version whose runtime image to keep warm")
+	fs.Parse(args)
+	if *size < 1 {
+		fmt.Fprintln(os.Stderr, "openhands: -size must be at least 1")
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	image := cfg.RuntimeImage()
+	if err := EnsureImage(ctx, cli, image); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Keeping %d warm runtime container(s) for %s. Ctrl-C tears the pool down.\n", *size, image)
+
+	ticker := time.NewTicker(poolInterval)
+	defer ticker.Stop()
+	for {
+		if err := reconcilePool(ctx, cli, image, *size); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "openhands: pool: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			// A fresh context: the signal context is already cancelled.
+			if err := drainPool(context.Background(), cli, image); err != nil {
+				fmt.Fprintf(os.Stderr, "openhands: pool teardown: %v\n", err)
+				return 1
+			}
+			fmt.Fprintln(os.Stderr, "Warm pool removed.")
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// listPool returns the warm containers for the given image.
+func listPool(ctx context.Context, cli *client.Client, image string) ([]types.Container, error) {
+	f := filters.NewArgs(
+		filters.Arg("label", LabelManaged+"=true"),
+		filters.Arg("label", LabelPool+"="+image),
+	)
+	return cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: f})
+}
+
+// reconcilePool creates or removes warm containers until exactly size of them
+// are running.
+func reconcilePool(ctx context.Context, cli *client.Client, image string, size int) error {
+	existing, err := listPool(ctx, cli, image)
+	if err != nil {
+		return err
+	}
+	running := 0
+	for _, c := range existing {
+		if c.State == "running" {
+			running++
+			continue
+		}
+		// Exited or created leftovers are dead weight; replace them.
+		if err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return err
+		}
+	}
+	for i := running; i < size; i++ {
+		if err := startWarmContainer(ctx, cli, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startWarmContainer creates and starts one warm runtime container. It idles
+// on sleep so it costs nothing but the memory of its page cache.
+func startWarmContainer(ctx context.Context, cli *client.Client, image string) error {
+	conf := &container.Config{
+		Image:      image,
+		Entrypoint: []string{"sleep", "infinity"},
+		Labels: map[string]string{
+			LabelManaged: "true",
+			LabelPool:    image,
+			LabelVersion: Version,
+		},
+	}
+	name := fmt.Sprintf("openhands-warm-%d", time.Now().UnixNano())
+	resp, err := cli.ContainerCreate(ctx, conf, &container.HostConfig{}, nil, nil, name)
+	if err != nil {
+		return fmt.Errorf("creating warm container: %w", err)
+	}
+	if err := cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return fmt.Errorf("starting warm container: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Warmed %s (%s)\n", name, resp.ID[:12])
+	return nil
+}
+
+// drainPool removes every warm container for the image.
+func drainPool(ctx context.Context, cli *client.Client, image string) error {
+	existing, err := listPool(ctx, cli, image)
+	if err != nil {
+		return err
+	}
+	for _, c := range existing {
+		if err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
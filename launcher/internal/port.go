@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// portFree reports whether the port can be bound on the given address, by
+// binding it. Checking up front turns docker's late "port is already
+// allocated" failure into an immediate, explicit error.
+func portFree(bind string, port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
+
+// findFreePort asks the kernel for an unused port on the bind address, so
+// the probe uses the same address family the publish spec will.
+func findFreePort(bind string) (int, error) {
+	ln, err := net.Listen("tcp", net.JoinHostPort(bind, "0"))
+	if err != nil {
+		return 0, fmt.Errorf("finding a free port on %s: %w", bind, err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port, nil
+}
+
+// resolvePort settles the host port before anything slow happens, so a taken
+// port fails (or is substituted) in milliseconds rather than after a
+// multi-GB image pull. A defaulted port is swapped silently-ish; an explicit
+// -port is only swapped under -port-retry, and otherwise fails with the
+// process holding it when that is discoverable. Ports on a remote daemon
+// are bound on the remote host and cannot be probed from here.
+func resolvePort(cfg *Config) error {
+	if cfg.remoteHost != "" || portFree(cfg.Bind, cfg.Port) {
+		return nil
+	}
+	if !cfg.portExplicit || cfg.PortRetry {
+		port, err := findFreePort(cfg.Bind)
+		if err != nil {
+			return err
+		}
+		warnf("Port %d is already in use on %s; using port %d instead", cfg.Port, cfg.Bind, port)
+		cfg.Port = port
+		return nil
+	}
+	if owner := portOwner(cfg.Port); owner != "" {
+		return fmt.Errorf("port %d is already in use on %s by %s; stop it, pick another with -port, or pass -port-retry to fall back to a free port", cfg.Port, cfg.Bind, owner)
+	}
+	return fmt.Errorf("port %d is already in use on %s; pick another with -port, or pass -port-retry to fall back to a free port", cfg.Port, cfg.Bind)
+}
+
+// portOwner names the process listening on the port, best-effort via lsof.
+// It returns "" whenever that cannot be determined (no lsof, Windows, a
+// process owned by another user).
+func portOwner(port int) string {
+	out, err := exec.Command("lsof", "-nP", "-iTCP:"+strconv.Itoa(port), "-sTCP:LISTEN", "-Fcp").Output()
+	if err != nil {
+		return ""
+	}
+	// -F output is line-oriented: "p<pid>" then "c<command>" per process.
+	var pid, cmd string
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "p") && pid == "":
+			pid = line[1:]
+		case strings.HasPrefix(line, "c") && cmd == "":
+			cmd = line[1:]
+		}
+	}
+	if pid == "" {
+		return ""
+	}
+	if cmd == "" {
+		return "PID " + pid
+	}
+	return fmt.Sprintf("%s (PID %s)", cmd, pid)
+}
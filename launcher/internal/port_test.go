@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+// busyPort binds a port on loopback for the duration of the test and
+// returns it.
+func busyPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestResolvePortFree(t *testing.T) {
+	port, err := findFreePort("127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{Bind: "127.0.0.1", Port: port, portExplicit: true}
+	if err := resolvePort(cfg); err != nil {
+		t.Fatalf("resolvePort on a free port: %v", err)
+	}
+	if cfg.Port != port {
+		t.Errorf("free port was substituted: %d -> %d", port, cfg.Port)
+	}
+}
+
+func TestResolvePortBusyDefaulted(t *testing.T) {
+	port := busyPort(t)
+	cfg := &Config{Bind: "127.0.0.1", Port: port}
+	if err := resolvePort(cfg); err != nil {
+		t.Fatalf("resolvePort: %v", err)
+	}
+	if cfg.Port == port {
+		t.Error("busy defaulted port should have been substituted")
+	}
+}
+
+func TestResolvePortBusyExplicit(t *testing.T) {
+	port := busyPort(t)
+	cfg := &Config{Bind: "127.0.0.1", Port: port, portExplicit: true}
+	err := resolvePort(cfg)
+	if err == nil {
+		t.Fatal("busy explicit port should fail without -port-retry")
+	}
+	if !strings.Contains(err.Error(), "-port-retry") {
+		t.Errorf("error should mention -port-retry: %v", err)
+	}
+	if cfg.Port != port {
+		t.Errorf("explicit port must not be substituted on failure: %d -> %d", port, cfg.Port)
+	}
+}
+
+func TestResolvePortBusyExplicitRetry(t *testing.T) {
+	port := busyPort(t)
+	cfg := &Config{Bind: "127.0.0.1", Port: port, portExplicit: true, PortRetry: true}
+	if err := resolvePort(cfg); err != nil {
+		t.Fatalf("resolvePort with -port-retry: %v", err)
+	}
+	if cfg.Port == port {
+		t.Error("-port-retry should have substituted a free port")
+	}
+}
+
+func TestResolvePortRemoteDaemon(t *testing.T) {
+	port := busyPort(t)
+	cfg := &Config{Bind: "127.0.0.1", Port: port, portExplicit: true, remoteHost: "build.internal"}
+	if err := resolvePort(cfg); err != nil {
+		t.Fatalf("resolvePort must not probe a remote daemon's port: %v", err)
+	}
+	if cfg.Port != port {
+		t.Errorf("remote port was substituted: %d -> %d", port, cfg.Port)
+	}
+}
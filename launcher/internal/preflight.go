@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/go-units"
+)
+
+// Thresholds above which bind mounts and repo indexing are known to get
+// painfully slow, especially on Docker Desktop's file sharing.
+const (
+	workspaceSizeWarn  = 2 << 30 // 2 GiB
+	workspaceFilesWarn = 200_000
+	largeFileWarn      = 256 << 20 // 256 MiB per file
+	// surveyFileCap bounds the walk so the pre-flight itself stays fast on
+	// pathological trees; past the cap the file count alone already
+	// warrants the warning.
+	surveyFileCap = 500_000
+)
+
+// workspaceSurvey summarizes what a walk of the workspace found.
+type workspaceSurvey struct {
+	Bytes      int64
+	Files      int
+	LargeFiles []string // relative paths of unusually large files
+	Truncated  bool     // the walk stopped at surveyFileCap
+}
+
+// surveyWorkspace walks the workspace and tallies size, file count and very
+// large files. Errors on individual entries are ignored; the survey is a
+// heuristic, not an audit.
+func surveyWorkspace(root string) workspaceSurvey {
+	var s workspaceSurvey
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if s.Files >= surveyFileCap {
+			s.Truncated = true
+			return filepath.SkipAll
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		s.Files++
+		s.Bytes += info.Size()
+		if info.Size() >= largeFileWarn {
+			if rel, err := filepath.Rel(root, path); err == nil {
+				s.LargeFiles = append(s.LargeFiles, rel)
+			}
+		}
+		return nil
+	})
+	return s
+}
+
+// surveyFindings renders the warnings a survey warrants, one per line, or nil
+// when the workspace is unremarkable.
+func surveyFindings(s workspaceSurvey) []string {
+	var out []string
+	if s.Bytes >= workspaceSizeWarn {
+		out = append(out, fmt.Sprintf("the workspace holds %s of data", units.BytesSize(float64(s.Bytes))))
+	}
+	if s.Files >= workspaceFilesWarn || s.Truncated {
+		count := fmt.Sprintf("%d", s.Files)
+		if s.Truncated {
+			count = "over " + count
+		}
+		out = append(out, fmt.Sprintf("the workspace holds %s files", count))
+	}
+	if len(s.LargeFiles) > 0 {
+		names := s.LargeFiles
+		if len(names) > 5 {
+			names = append(append([]string{}, names[:5]...), "...")
+		}
+		out = append(out, fmt.Sprintf("it contains very large files: %s", strings.Join(names, ", ")))
+	}
+	return out
+}
+
+// preflightWorkspace warns when the workspace is large enough to make the
+// bind mount and repo indexing slow, and suggests the usual remedies. It
+// never blocks the launch.
+func preflightWorkspace(root string) {
+	findings := surveyFindings(surveyWorkspace(root))
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Workspace pre-flight warnings:")
+	for _, f := range findings {
+		fmt.Fprintf(os.Stderr, "  - %s\n", f)
+	}
+	fmt.Fprintln(os.Stderr, "Large workspaces make the bind mount and indexing slow; consider pointing the launcher at a subdirectory, or moving build artifacts (node_modules, target, dist) out of the tree.")
+}
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSurveyFindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		survey workspaceSurvey
+		want   []string // substrings, one per expected finding
+	}{
+		{"small workspace", workspaceSurvey{Bytes: 1 << 20, Files: 100}, nil},
+		{"too big", workspaceSurvey{Bytes: 3 << 30, Files: 100}, []string{"3GiB of data"}},
+		{"too many files", workspaceSurvey{Files: 250_000}, []string{"250000 files"}},
+		{"truncated walk", workspaceSurvey{Files: 500_000, Truncated: true}, []string{"over 500000 files"}},
+		{"large file", workspaceSurvey{Files: 10, LargeFiles: []string{"data/dump.bin"}}, []string{"data/dump.bin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := surveyFindings(tt.survey)
+			if len(got) != len(tt.want) {
+				t.Fatalf("surveyFindings() = %q, want %d finding(s)", got, len(tt.want))
+			}
+			for i, sub := range tt.want {
+				if !strings.Contains(got[i], sub) {
+					t.Errorf("finding %d = %q, want substring %q", i, got[i], sub)
+				}
+			}
+		})
+	}
+}
+
+func TestSurveyFindingsTruncatesLargeFileList(t *testing.T) {
+	s := workspaceSurvey{LargeFiles: []string{"a", "b", "c", "d", "e", "f", "g"}}
+	got := surveyFindings(s)
+	if len(got) != 1 || !strings.Contains(got[0], "...") || strings.Contains(got[0], "f,") {
+		t.Fatalf("surveyFindings() = %q, want first five names plus ellipsis", got)
+	}
+}
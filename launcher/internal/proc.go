@@ -0,0 +1,596 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
+)
+
+// ContainerName is the fixed name given to the app container, matching the
+// name used in the documented docker run invocation.
+const ContainerName = "openhands-app"
+
+// Labels applied to every container the launcher creates, so that launcher
+// instances can be told apart from containers users started by hand.
+const (
+	LabelManaged   = "dev.openhands.launcher"
+	LabelWorkspace = "dev.openhands.workspace"
+	LabelVersion   = "dev.openhands.version"
+	LabelPort      = "dev.openhands.port"
+)
+
+// launchLabels returns the labels recorded on every launched app container.
+// Discovery (ps, stop, logs, clean) filters on LabelManaged rather than on
+// the container name, so a custom -name does not break management; the
+// others let ps and the JSON output reconstruct the launch without the
+// registry, including under host networking where docker reports no port
+// mappings.
+func launchLabels(cfg *Config) map[string]string {
+	return map[string]string{
+		LabelManaged:   "true",
+		LabelWorkspace: cfg.mountWorkspace(),
+		LabelVersion:   cfg.Tag,
+		LabelPort:      strconv.Itoa(cfg.Port),
+	}
+}
+
+// launchLabelPairs is launchLabels rendered as sorted key=value pairs, the
+// form `docker run --label` and compose files take.
+func launchLabelPairs(cfg *Config) []string {
+	labels := launchLabels(cfg)
+	pairs := make([]string, 0, len(labels))
+	for _, k := range sortedKeys(labels) {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return pairs
+}
+
+// NewDockerClient connects to the container engine using the standard
+// environment (DOCKER_HOST and friends) and negotiates an API version it
+// supports. On machines without Docker the Podman compatibility socket is
+// used instead; see detectEngine.
+func NewDockerClient() (*client.Client, error) {
+	if socketOverride != "" && !fileExists(socketOverride) {
+		return nil, fmt.Errorf("-docker-socket %s does not exist; check the path (rootless Docker uses $XDG_RUNTIME_DIR/docker.sock)", socketOverride)
+	}
+	dockerHost := os.Getenv("DOCKER_HOST")
+	if contextHost != "" {
+		// A selected docker context replaces the environment's endpoint.
+		dockerHost = contextHost
+	}
+	host, eng := detectEngine(socketOverride, dockerHost, os.Getenv("XDG_RUNTIME_DIR"), fileExists)
+	currentEngine = eng
+	if contextHost != "" && host == "" {
+		// detectEngine leaves DOCKER_HOST endpoints to FromEnv, but a
+		// context endpoint is not in the environment.
+		host = contextHost
+	}
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+	if host != "" {
+		opts = append(opts, client.WithHost(host))
+		fmt.Fprintf(os.Stderr, "Using %s\n", describeEngine(eng))
+	}
+	cli, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s client: %w", eng.Kind, err)
+	}
+	return cli, nil
+}
+
+// PingDaemon verifies that the engine is reachable before we start building
+// containers, so connection problems surface as one clear error. The three
+// failure modes that look identical in docker's own output - nothing
+// installed, daemon down, socket permissions - each get their own message.
+func PingDaemon(ctx context.Context, cli *client.Client) error {
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err := cli.Ping(pingCtx)
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EACCES):
+		return fmt.Errorf("the %s socket at %s denied access; add yourself to the docker group (usermod -aG docker $USER) and log in again: %w",
+			currentEngine.Kind, currentEngine.SocketPath, err)
+	case os.Getenv("DOCKER_HOST") == "" && !fileExists(currentEngine.SocketPath):
+		return fmt.Errorf("no container engine found at %s; install Docker (https://docs.docker.com/get-docker/) or Podman, or point DOCKER_HOST at a running engine: %w",
+			currentEngine.SocketPath, err)
+	default:
+		return fmt.Errorf("cannot reach the %s daemon (%s): %w", currentEngine.Kind, engineHint(currentEngine), err)
+	}
+}
+
+// EnsureImage makes sure the given image is present locally, pulling it if
+// it is not. Pull progress is streamed to the terminal.
+func EnsureImage(ctx context.Context, cli *client.Client, ref string) error {
+	_, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err == nil {
+		return nil
+	}
+	if !errdefs.IsNotFound(err) {
+		return fmt.Errorf("inspecting image %s: %w", ref, err)
+	}
+	return withRetry(ctx, "pull "+ref, func() error {
+		return pullImage(ctx, cli, ref, "")
+	})
+}
+
+// EnsureImagePolicy is EnsureImage under a -pull policy: "always" pulls even
+// when the image is present (picking up a moved tag), "missing" is the
+// default behavior, and "never" fails fast with the exact pull command when
+// the image is absent, instead of silently downloading gigabytes. A
+// non-empty platform is forced on every pull, and under "missing" a local
+// image of the wrong platform counts as missing.
+func EnsureImagePolicy(ctx context.Context, cli *client.Client, ref, policy, platform string) error {
+	switch policy {
+	case "always":
+		return withRetry(ctx, "pull "+ref, func() error {
+			return pullImage(ctx, cli, ref, platform)
+		})
+	case "never":
+		if _, _, err := cli.ImageInspectWithRaw(ctx, ref); err != nil {
+			if errdefs.IsNotFound(err) {
+				return fmt.Errorf("image %s is not present locally and -pull never is set; run `docker pull %s` first", ref, ref)
+			}
+			return fmt.Errorf("inspecting image %s: %w", ref, err)
+		}
+		return nil
+	default:
+		if platform != "" {
+			if got, err := imagePlatform(ctx, cli, ref); err == nil && got != "" && got != platform {
+				return withRetry(ctx, "pull "+ref, func() error {
+					return pullImage(ctx, cli, ref, platform)
+				})
+			}
+		}
+		return EnsureImage(ctx, cli, ref)
+	}
+}
+
+func pullImage(ctx context.Context, cli *client.Client, ref, platform string) error {
+	rc, err := cli.ImagePull(ctx, ref, types.ImagePullOptions{Platform: platform})
+	if err != nil {
+		return explainPullError(ref, err)
+	}
+	defer rc.Close()
+	if err := displayPullProgress(rc, ref, os.Stderr); err != nil {
+		return explainPullError(ref, err)
+	}
+	return nil
+}
+
+// explainPullError turns docker's terse pull failures into actionable errors.
+// The common unactionable one is "no matching manifest": the tag exists but
+// was not published for the host architecture, which hits Apple Silicon and
+// other arm64 hosts with older tags.
+func explainPullError(ref string, err error) error {
+	msg := err.Error()
+	if strings.Contains(msg, "no matching manifest") || strings.Contains(msg, "does not match the specified platform") {
+		return fmt.Errorf("image %s has no build for this machine's architecture (%s/%s).\n"+
+			"Either use a newer tag that is published multi-arch, or run the amd64 build under emulation with DOCKER_DEFAULT_PLATFORM=linux/amd64 (slower, but works)", ref, runtime.GOOS, runtime.GOARCH)
+	}
+	return fmt.Errorf("pulling image %s: %w", ref, err)
+}
+
+// containerEnv builds the environment passed to the app container.
+func containerEnv(cfg *Config) []string {
+	env := []string{
+		"SANDBOX_USER_ID=" + strconv.Itoa(cfg.sandboxUID()),
+	}
+	if useSandboxVolumes(cfg) {
+		env = append(env, "SANDBOX_VOLUMES="+sandboxVolumesSpec(cfg))
+	} else {
+		env = append(env, "WORKSPACE_MOUNT_PATH="+cfg.mountWorkspace())
+	}
+	if cfg.NoDockerSock {
+		// The server's local runtime executes the agent inside the app
+		// container itself; no sandbox container is ever started.
+		env = append(env, "RUNTIME=local")
+	} else {
+		env = append(env, "SANDBOX_RUNTIME_CONTAINER_IMAGE="+cfg.RuntimeImage())
+	}
+	if cfg.stateDir != "" {
+		// Point the server's file store at the mounted state directory.
+		env = append(env, "FILE_STORE_PATH="+StateMountPath)
+	}
+	if currentEngine.RemoteHost != "" {
+		// No socket is mounted for a remote daemon; the server reaches it
+		// the same way this client does.
+		env = append(env, "DOCKER_HOST="+currentEngine.RemoteHost)
+	}
+	if cfg.GPUs != "" {
+		// The server passes GPU access on to the sandbox runtime it starts.
+		env = append(env, "SANDBOX_ENABLE_GPU=true")
+	}
+	// Proxy settings forwarded from the shell; see resolveProxyEnv. They
+	// come before the user's -env variables, which therefore win.
+	env = append(env, cfg.proxyEnv...)
+	if cfg.LLM.Model != "" {
+		env = append(env, "LLM_MODEL="+cfg.LLM.Model)
+	}
+	if cfg.LLM.APIKey != "" {
+		// With a key file mounted, only its location goes into the
+		// environment; see writeLLMKeyFile.
+		if cfg.llmKeyFile != "" {
+			env = append(env, "LLM_API_KEY_FILE="+llmKeyMountPath)
+		} else {
+			env = append(env, "LLM_API_KEY="+cfg.LLM.APIKey)
+		}
+	}
+	if cfg.LLM.BaseURL != "" {
+		env = append(env, "LLM_BASE_URL="+cfg.LLM.BaseURL)
+	}
+	if cfg.LLM.APIVersion != "" {
+		env = append(env, "LLM_API_VERSION="+cfg.LLM.APIVersion)
+	}
+	if cfg.Budget > 0 {
+		env = append(env, fmt.Sprintf("MAX_BUDGET_PER_TASK=%g", cfg.Budget))
+	}
+	if !useSandboxVolumes(cfg) && len(cfg.ExtraMounts) > 0 {
+		// Even legacy servers honor SANDBOX_VOLUMES for extra directories:
+		// it tells the server to bind them into the sandbox runtime, since
+		// mounting them only into the app container would leave the agent
+		// unable to see them.
+		volumes := append([]string{cfg.Workspace + ":" + WorkspaceMountPath + ":" + cfg.workspaceMode()}, cfg.ExtraMounts...)
+		env = append(env, "SANDBOX_VOLUMES="+strings.Join(volumes, ","))
+	}
+	// Forward timezone and locale so timestamps and generated code match
+	// the user's environment instead of UTC/C. The sandbox gets the same
+	// values via the SANDBOX_ENV_ prefix.
+	if tz := cfg.timezone(); tz != "" {
+		env = append(env, "TZ="+tz, "SANDBOX_ENV_TZ="+tz)
+	}
+	for _, name := range []string{"LANG", "LC_ALL"} {
+		if v := os.Getenv(name); v != "" {
+			env = append(env, name+"="+v, "SANDBOX_ENV_"+name+"="+v)
+		}
+	}
+	// SANDBOX_ENV_-prefixed variables are stripped by the server and set
+	// only inside the sandbox runtime, which keeps them out of the app
+	// container's own environment. Secrets are applied last so a
+	// secrets-file entry wins over a plain --sandbox-env of the same name.
+	for _, k := range sortedKeys(cfg.SandboxEnv) {
+		if _, shadowed := cfg.sandboxSecrets[k]; shadowed {
+			continue
+		}
+		env = append(env, "SANDBOX_ENV_"+k+"="+cfg.SandboxEnv[k])
+	}
+	for _, k := range sortedKeys(cfg.sandboxSecrets) {
+		env = append(env, "SANDBOX_ENV_"+k+"="+cfg.sandboxSecrets[k])
+	}
+	// User-supplied -env/-env-file variables come last so an explicit one
+	// wins over anything the launcher set, as warned in resolveEnv.
+	for _, k := range sortedKeys(cfg.env) {
+		env = append(env, k+"="+cfg.env[k])
+	}
+	return env
+}
+
+// taskSuggestsBrowsing is a cheap heuristic for tasks that will exercise the
+// browsing agent, used only to suggest a larger --shm-size.
+func taskSuggestsBrowsing(task string) bool {
+	task = strings.ToLower(task)
+	for _, needle := range []string{"browse", "browser", "screenshot", "http://", "https://", "web page", "website"} {
+		if strings.Contains(task, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// hardenedCaps are the capabilities the app container still needs under
+// --hardened: the server drops privileges to the sandbox user (SETUID/SETGID)
+// and fixes up workspace file ownership (CHOWN, DAC_OVERRIDE, FOWNER).
+var hardenedCaps = []string{"CHOWN", "DAC_OVERRIDE", "FOWNER", "SETGID", "SETUID"}
+
+// containerCmd returns the command override for the app container. Serving
+// the UI uses the image's default entrypoint; a task switches the container
+// to the backend's headless mode instead.
+func containerCmd(cfg *Config) []string {
+	if cfg.Task == "" {
+		return nil
+	}
+	cmd := []string{"python", "-m", "openhands.core.main", "-t", cfg.Task}
+	if cfg.MaxIterations > 0 {
+		cmd = append(cmd, "-i", strconv.Itoa(cfg.MaxIterations))
+	}
+	return cmd
+}
+
+// CreateContainer creates (but does not start) the app container and returns
+// its ID.
+func CreateContainer(ctx context.Context, cli *client.Client, cfg *Config) (string, error) {
+	uiPort, err := nat.NewPort("tcp", strconv.Itoa(DefaultPort))
+	if err != nil {
+		return "", err
+	}
+	if cfg.LLM.APIKey != "" && !cfg.InsecureEnvSecrets {
+		// The key is handed over as a mounted file, not an environment
+		// variable; containerEnv switches to the pointer variable when
+		// llmKeyFile is set.
+		path, err := writeLLMKeyFile(cfg.LLM.APIKey)
+		if err != nil {
+			return "", fmt.Errorf("storing LLM API key: %w", err)
+		}
+		cfg.llmKeyFile = path
+	}
+	env := containerEnv(cfg)
+	// Matching shell variables ride along after the explicit settings; see
+	// forwardedEnvKeys for the allowlist and precedence rules.
+	for _, k := range forwardedEnvKeys(cfg, env) {
+		env = append(env, k+"="+os.Getenv(k))
+	}
+	conf := &container.Config{
+		Image:        cfg.AppImage(),
+		Env:          env,
+		Cmd:          containerCmd(cfg),
+		User:         cfg.User,
+		Tty:          true,
+		OpenStdin:    true,
+		ExposedPorts: nat.PortSet{uiPort: struct{}{}},
+		Labels:       launchLabels(cfg),
+	}
+	var binds []string
+	if !useSandboxVolumes(cfg) {
+		// SANDBOX_VOLUMES-aware servers mount the workspace into the sandbox
+		// runtime themselves; only legacy ones need it in the app container.
+		binds = append(binds, hostMountPath(cfg.mountWorkspace())+":"+WorkspaceMountPath+cfg.workspaceBindSuffix())
+	}
+	if sock := socketBind(currentEngine); sock != "" && !cfg.NoDockerSock {
+		binds = append(binds, sock)
+	}
+	if cfg.stateDir != "" {
+		// Conversations and settings persist on the host across container
+		// removals; see resolveStateDir for ownership.
+		binds = append(binds, hostMountPath(cfg.stateDir)+":"+StateMountPath)
+	}
+	if cfg.llmKeyFile != "" {
+		binds = append(binds, hostMountPath(cfg.llmKeyFile)+":"+llmKeyMountPath+":ro")
+	}
+	binds = append(binds, cfg.ExtraMounts...)
+	if cfg.ServerConfig != "" {
+		// The backend reads /app/config.toml on startup; mounting it
+		// read-only covers options that have no env-var equivalent.
+		binds = append(binds, hostMountPath(cfg.ServerConfig)+":/app/config.toml:ro")
+	}
+	if cfg.shmSizeBytes == 0 && taskSuggestsBrowsing(cfg.Task) {
+		infof("Hint: this task looks like it will drive a browser; Chromium needs more shared memory than Docker's 64MB default. Consider --shm-size 2g.")
+	}
+	securityOpts := cfg.securityOpts
+	var capDrop, capAdd []string
+	if cfg.Hardened {
+		capDrop = []string{"ALL"}
+		capAdd = hardenedCaps
+		securityOpts = append(append([]string{}, securityOpts...), "no-new-privileges")
+		// A read-only root filesystem is not compatible with the app
+		// image, which writes server state under /app at runtime, so the
+		// preset stops at capabilities and privilege escalation.
+		infof("Hardened mode: dropping all capabilities except %s and disabling privilege escalation.", strings.Join(hardenedCaps, ", "))
+		infof("Note: the mounted docker socket still grants the app container control over the daemon; remove it only if your setup provides the sandbox runtime another way.")
+	}
+	hostConf := &container.HostConfig{
+		// Exited containers pile up otherwise; -keep-container opts out
+		// when the post-mortem filesystem is wanted for debugging.
+		AutoRemove:  !cfg.KeepContainer,
+		Binds:       binds,
+		ShmSize:     cfg.shmSizeBytes,
+		Resources:   containerResources(cfg),
+		SecurityOpt: securityOpts,
+		CapDrop:     capDrop,
+		CapAdd:      capAdd,
+		PortBindings: nat.PortMap{
+			uiPort: []nat.PortBinding{{
+				HostIP:   cfg.Bind,
+				HostPort: strconv.Itoa(cfg.Port),
+			}},
+		},
+		ExtraHosts: []string{"host.docker.internal:host-gateway"},
+	}
+	if cfg.Network != "" {
+		hostConf.NetworkMode = container.NetworkMode(cfg.Network)
+	}
+	if cfg.Network == "host" {
+		// Docker rejects port publishing combined with host networking, and
+		// host.docker.internal is unnecessary when the container shares the
+		// host's network namespace.
+		hostConf.PortBindings = nil
+		hostConf.ExtraHosts = nil
+		conf.ExposedPorts = nil
+	}
+	if currentEngine.Kind == "podman" && currentEngine.Rootless {
+		// Rootless Podman maps the invoking user to root inside the
+		// container by default; keep-id preserves the UID so workspace
+		// files keep their ownership, matching what SANDBOX_USER_ID
+		// assumes.
+		hostConf.UsernsMode = "keep-id"
+	}
+	if err := applyDockerArgs(cfg, conf, hostConf); err != nil {
+		return "", err
+	}
+	name := cfg.containerName()
+	resp, err := cli.ContainerCreate(ctx, conf, hostConf, nil, cfg.platform, name)
+	if err != nil {
+		if errdefs.IsConflict(err) {
+			return "", fmt.Errorf("a container named %s already exists; remove it with `docker rm -f %s` and retry", name, name)
+		}
+		return "", fmt.Errorf("creating container: %w", err)
+	}
+	return resp.ID, nil
+}
+
+// reuseOrCreateContainer resolves what to do when a container already exists
+// under the configured name, instead of surfacing docker's conflict error. A
+// running one is attached to by printing its URL (use -recreate to replace
+// it); a stopped one is resumed with -resume or replaced with -recreate. The
+// empty id means an existing instance was attached to and there is nothing
+// left to launch.
+func reuseOrCreateContainer(ctx context.Context, cli *client.Client, cfg *Config) (string, error) {
+	name := cfg.containerName()
+	insp, err := cli.ContainerInspect(ctx, name)
+	if errdefs.IsNotFound(err) {
+		return CreateContainer(ctx, cli, cfg)
+	}
+	if err != nil {
+		return "", fmt.Errorf("inspecting container %s: %w", name, err)
+	}
+	if cfg.Recreate {
+		if err := cli.ContainerRemove(ctx, insp.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return "", fmt.Errorf("replacing container %s: %w", name, err)
+		}
+		return CreateContainer(ctx, cli, cfg)
+	}
+	if insp.State != nil && insp.State.Running {
+		url := publishedURL(insp)
+		if url == "" {
+			url = cfg.uiURL()
+		}
+		infof("%s is already running at %s (use -recreate to replace it)", name, url)
+		openBrowser(url, cfg.Browser)
+		return "", nil
+	}
+	if cfg.Resume {
+		return insp.ID, nil
+	}
+	return "", fmt.Errorf("a container named %s exists but is stopped; start it again with -resume, or replace it with -recreate", name)
+}
+
+// publishedURL derives the UI URL from a container's port bindings, so
+// attaching to an existing instance reports where it actually listens rather
+// than where this invocation's flags would have put it.
+func publishedURL(insp types.ContainerJSON) string {
+	if insp.HostConfig == nil {
+		return ""
+	}
+	for _, bindings := range insp.HostConfig.PortBindings {
+		for _, b := range bindings {
+			host := b.HostIP
+			if host == "" || host == "0.0.0.0" || host == "::" {
+				host = "localhost"
+			}
+			return fmt.Sprintf("http://%s:%s", host, b.HostPort)
+		}
+	}
+	return ""
+}
+
+// checkNetwork verifies a named -network exists before launching, so a typo
+// fails with a pointer at `docker network ls` instead of docker's create
+// error. The builtin modes need no lookup.
+func checkNetwork(ctx context.Context, cli *client.Client, name string) error {
+	switch name {
+	case "", "host", "bridge", "none", "default":
+		return nil
+	}
+	if _, err := cli.NetworkInspect(ctx, name, types.NetworkInspectOptions{}); err != nil {
+		if errdefs.IsNotFound(err) {
+			return fmt.Errorf("network %q does not exist; create it with `docker network create %s` or pick one from `docker network ls`", name, name)
+		}
+		return fmt.Errorf("inspecting network %q: %w", name, err)
+	}
+	return nil
+}
+
+// containerResources assembles the resource section of the host config.
+func containerResources(cfg *Config) container.Resources {
+	res := container.Resources{
+		Ulimits:  cfg.ulimits,
+		Memory:   cfg.memoryBytes,
+		NanoCPUs: cfg.nanoCPUs,
+	}
+	if cfg.gpus != nil {
+		res.DeviceRequests = []container.DeviceRequest{cfg.gpus.deviceRequest()}
+	}
+	return res
+}
+
+// StartContainer starts a previously created container and begins streaming
+// its output to the terminal. The returned channel yields the container's
+// exit status once it stops.
+func StartContainer(ctx context.Context, cli *client.Client, cfg *Config, id string) (<-chan container.WaitResponse, <-chan error, error) {
+	interactive := interactiveSession()
+	attach, err := cli.ContainerAttach(ctx, id, types.ContainerAttachOptions{
+		Stream: true,
+		Stdin:  interactive,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("attaching to container: %w", err)
+	}
+	err = withRetry(ctx, "start container", func() error {
+		return cli.ContainerStart(ctx, id, types.ContainerStartOptions{})
+	})
+	if err != nil {
+		attach.Close()
+		return nil, nil, fmt.Errorf("starting container: %w", err)
+	}
+	cleanup := func() {}
+	if interactive {
+		cleanup = setupInteractive(ctx, cli, id, attach)
+	}
+	var out io.Writer = os.Stdout
+	width, height := terminalSize(os.Stdout.Fd())
+	if rec, err := newCastRecorder(cfg.Workspace, width, height); err != nil {
+		warnf("session recording disabled: %v", err)
+	} else {
+		infof("Recording session to %s (replay with `openhands play`)", rec.Path)
+		out = io.MultiWriter(os.Stdout, rec)
+	}
+	if cfg.logWriter != nil {
+		// The launch log gets the same stream, so scrolled-away output
+		// survives for bug reports.
+		out = io.MultiWriter(out, cfg.logWriter)
+	}
+	go func() {
+		defer attach.Close()
+		// The container runs with a TTY, so the attach stream is raw
+		// rather than multiplexed.
+		io.Copy(out, attach.Reader)
+		cleanup()
+	}()
+	waitCh, errCh := cli.ContainerWait(ctx, id, container.WaitConditionNotRunning)
+	return waitCh, errCh, nil
+}
+
+// imageDigest returns the repo digest of a local image, or "" when it is
+// not known (e.g. locally built images).
+func imageDigest(ctx context.Context, cli *client.Client, ref string) string {
+	insp, _, err := cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil || len(insp.RepoDigests) == 0 {
+		return ""
+	}
+	return insp.RepoDigests[0]
+}
+
+// ContainerLogs returns the container's log stream, used to show context when
+// a launch fails after the container was started.
+func ContainerLogs(ctx context.Context, cli *client.Client, id string, tail string) (io.ReadCloser, error) {
+	return cli.ContainerLogs(ctx, id, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       tail,
+	})
+}
+
+// ContainerLogsFollow is like ContainerLogs but keeps following new output.
+func ContainerLogsFollow(ctx context.Context, cli *client.Client, id string, tail string) (io.ReadCloser, error) {
+	return cli.ContainerLogs(ctx, id, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Tail:       tail,
+	})
+}
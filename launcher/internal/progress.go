@@ -0,0 +1,265 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// pullTracker aggregates docker's per-layer pull progress into a single
+// overall percentage, so a pull renders as one updating line instead of a
+// scrolling list of layer IDs.
+type pullTracker struct {
+	layers  map[string]*layerProgress
+	started time.Time
+}
+
+type layerProgress struct {
+	current  int64
+	total    int64
+	complete bool
+}
+
+func newPullTracker() *pullTracker {
+	return &pullTracker{
+		layers:  make(map[string]*layerProgress),
+		started: time.Now(),
+	}
+}
+
+// update records one progress message and returns the new overall state.
+func (t *pullTracker) update(msg *jsonmessage.JSONMessage) {
+	if msg.ID == "" {
+		return
+	}
+	lp := t.layers[msg.ID]
+	if lp == nil {
+		lp = &layerProgress{}
+		t.layers[msg.ID] = lp
+	}
+	switch msg.Status {
+	case "Downloading", "Extracting":
+		if msg.Progress != nil && msg.Progress.Total > 0 {
+			lp.current = msg.Progress.Current
+			lp.total = msg.Progress.Total
+		}
+	case "Download complete", "Pull complete", "Already exists":
+		lp.complete = true
+		if lp.total > 0 {
+			lp.current = lp.total
+		}
+	}
+}
+
+// percent returns overall progress in [0, 100]. Layers whose size is not yet
+// known contribute nothing, so the value can only grow as layers report in.
+func (t *pullTracker) percent() float64 {
+	var current, total int64
+	for _, lp := range t.layers {
+		if lp.total == 0 {
+			continue
+		}
+		current += lp.current
+		total += lp.total
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(current) / float64(total) * 100
+}
+
+// eta estimates time remaining from the average rate so far. It returns zero
+// until there is enough progress to extrapolate from.
+func (t *pullTracker) eta() time.Duration {
+	pct := t.percent()
+	if pct < 1 {
+		return 0
+	}
+	elapsed := time.Since(t.started)
+	remaining := time.Duration(float64(elapsed) * (100 - pct) / pct)
+	return remaining.Round(time.Second)
+}
+
+// renderLine formats the single-line progress display.
+func (t *pullTracker) renderLine(ref string) string {
+	line := fmt.Sprintf("Pulling %s: %3.0f%%", ref, t.percent())
+	if eta := t.eta(); eta > 0 {
+		line += fmt.Sprintf(" (about %s left)", eta)
+	}
+	return line
+}
+
+// displayPullProgress consumes a docker pull progress stream and renders it
+// as a single updating line on a terminal, or occasional plain lines when
+// output is not a terminal.
+func displayPullProgress(rc io.Reader, ref string, out *os.File) error {
+	tracker := newPullTracker()
+	isTerm := isTerminal(out.Fd())
+	dec := json.NewDecoder(rc)
+	lastLen := 0
+	lastPct := -1.0
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		tracker.update(&msg)
+		pct := tracker.percent()
+		if isTerm {
+			line := tracker.renderLine(ref)
+			pad := ""
+			if n := lastLen - len(line); n > 0 {
+				pad = strings.Repeat(" ", n)
+			}
+			fmt.Fprintf(out, "\r%s%s", line, pad)
+			lastLen = len(line)
+		} else if pct-lastPct >= 10 {
+			fmt.Fprintf(out, "%s\n", tracker.renderLine(ref))
+			lastPct = pct
+		}
+	}
+	if isTerm {
+		fmt.Fprintf(out, "\rPulled %s%s\n", ref, strings.Repeat(" ", lastLen))
+	} else {
+		fmt.Fprintf(out, "Pulled %s\n", ref)
+	}
+	return nil
+}
+
+// stageReporter renders the launch lifecycle ("checking docker", "starting
+// container", ...) so the gap between hitting enter and the browser opening
+// is never silent. On a terminal the current stage sits behind a spinner
+// and is rewritten in place; otherwise every stage is a plain timestamped
+// line, so CI logs stay readable. Stages arrive over a channel, keeping the
+// rendering decoupled from the launch path and testable with a plain
+// writer.
+type stageReporter struct {
+	events chan string
+	quit   chan struct{}
+	done   chan struct{}
+	stop   sync.Once
+}
+
+// spinnerFrames cycle behind the stage text on a terminal.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+func newStageReporter(w io.Writer, tty bool) *stageReporter {
+	// The channel is unbuffered so a Stage call returns only once the
+	// renderer has taken the event; a Finish right after a Stage can then
+	// never drop it.
+	r := &stageReporter{
+		events: make(chan string),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.render(w, tty)
+	return r
+}
+
+// startStages builds the reporter for a launch. Quiet mode keeps its
+// contract of printing nothing but the final URL, and headless task runs
+// already stream the task's own output, so both get a nil reporter - every
+// method no-ops on nil, sparing the call sites the checks.
+func startStages(cfg *Config) *stageReporter {
+	if quietMode() || cfg.Task != "" {
+		return nil
+	}
+	return newStageReporter(os.Stderr, isTerminal(os.Stderr.Fd()))
+}
+
+// Stage announces the next lifecycle step.
+func (r *stageReporter) Stage(format string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+	select {
+	case <-r.quit:
+	case r.events <- fmt.Sprintf(format, args...):
+	}
+}
+
+// Clear blanks the spinner line without terminating the reporter, used
+// while something else (a docker pull) owns the terminal line.
+func (r *stageReporter) Clear() {
+	if r == nil {
+		return
+	}
+	select {
+	case <-r.quit:
+	case r.events <- "":
+	}
+}
+
+// Finish terminates the renderer and erases any spinner line, so errors and
+// the final URL start on a clean line. Safe to call repeatedly.
+func (r *stageReporter) Finish() {
+	if r == nil {
+		return
+	}
+	r.stop.Do(func() {
+		close(r.quit)
+		<-r.done
+	})
+}
+
+func (r *stageReporter) render(w io.Writer, tty bool) {
+	defer close(r.done)
+	if !tty {
+		for {
+			select {
+			case <-r.quit:
+				return
+			case text := <-r.events:
+				if text != "" {
+					fmt.Fprintf(w, "[%s] %s\n", time.Now().Format("15:04:05"), text)
+				}
+			}
+		}
+	}
+	ticker := time.NewTicker(120 * time.Millisecond)
+	defer ticker.Stop()
+	var text string
+	frame := 0
+	lastLen := 0
+	redraw := func() {
+		line := ""
+		if text != "" {
+			line = spinnerFrames[frame%len(spinnerFrames)] + " " + text
+		}
+		pad := ""
+		if n := lastLen - len(line); n > 0 {
+			pad = strings.Repeat(" ", n)
+		}
+		if line == "" && lastLen == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\r%s%s", line, pad)
+		lastLen = len(line)
+	}
+	for {
+		select {
+		case <-r.quit:
+			if lastLen > 0 {
+				fmt.Fprintf(w, "\r%s\r", strings.Repeat(" ", lastLen))
+			}
+			return
+		case text = <-r.events:
+			redraw()
+		case <-ticker.C:
+			frame++
+			redraw()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+func TestPullTrackerPercent(t *testing.T) {
+	tr := newPullTracker()
+	if got := tr.percent(); got != 0 {
+		t.Fatalf("empty tracker percent = %v, want 0", got)
+	}
+	tr.update(&jsonmessage.JSONMessage{
+		ID:       "aaa",
+		Status:   "Downloading",
+		Progress: &jsonmessage.JSONProgress{Current: 50, Total: 100},
+	})
+	tr.update(&jsonmessage.JSONMessage{
+		ID:       "bbb",
+		Status:   "Downloading",
+		Progress: &jsonmessage.JSONProgress{Current: 0, Total: 100},
+	})
+	if got := tr.percent(); got != 25 {
+		t.Fatalf("percent = %v, want 25", got)
+	}
+	tr.update(&jsonmessage.JSONMessage{ID: "aaa", Status: "Download complete"})
+	tr.update(&jsonmessage.JSONMessage{
+		ID:       "bbb",
+		Status:   "Downloading",
+		Progress: &jsonmessage.JSONProgress{Current: 100, Total: 100},
+	})
+	if got := tr.percent(); got != 100 {
+		t.Fatalf("percent = %v, want 100", got)
+	}
+}
+
+func TestPullTrackerIgnoresMessagesWithoutID(t *testing.T) {
+	tr := newPullTracker()
+	tr.update(&jsonmessage.JSONMessage{Status: "Status: Downloaded newer image"})
+	if len(tr.layers) != 0 {
+		t.Fatalf("expected no layers tracked, got %d", len(tr.layers))
+	}
+}
+
+func TestStageReporterPlain(t *testing.T) {
+	var buf bytes.Buffer
+	r := newStageReporter(&buf, false)
+	r.Stage("checking docker")
+	r.Stage("waiting for server on :%d", 3000)
+	r.Clear()
+	r.Finish()
+	out := buf.String()
+	if !strings.Contains(out, "checking docker") || !strings.Contains(out, "waiting for server on :3000") {
+		t.Errorf("plain output missing stages:\n%s", out)
+	}
+	if got := strings.Count(out, "\n"); got != 2 {
+		t.Errorf("plain output has %d lines, want 2 (Clear must not print):\n%q", got, out)
+	}
+	if strings.Contains(out, "\r") {
+		t.Errorf("plain output contains carriage returns:\n%q", out)
+	}
+}
+
+func TestStageReporterTTY(t *testing.T) {
+	var buf bytes.Buffer
+	r := newStageReporter(&buf, true)
+	r.Stage("starting container")
+	r.Finish()
+	out := buf.String()
+	if !strings.Contains(out, "starting container") {
+		t.Errorf("tty output missing the stage text:\n%q", out)
+	}
+	if !strings.HasPrefix(out, "\r") {
+		t.Errorf("tty output does not rewrite in place:\n%q", out)
+	}
+	if !strings.HasSuffix(out, "\r") {
+		t.Errorf("Finish did not leave the cursor on a cleared line:\n%q", out)
+	}
+}
+
+func TestStageReporterNil(t *testing.T) {
+	var r *stageReporter
+	r.Stage("checking docker")
+	r.Clear()
+	r.Finish()
+}
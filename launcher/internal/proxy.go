@@ -0,0 +1,68 @@
+package internal
+
+import "strings"
+
+// proxyVarNames are the proxy settings forwarded from the user's shell, in
+// both spellings tools conventionally honor.
+var proxyVarNames = []string{
+	"HTTP_PROXY", "http_proxy",
+	"HTTPS_PROXY", "https_proxy",
+	"NO_PROXY", "no_proxy",
+}
+
+// resolveProxyEnv collects the shell's proxy variables as KEY=VALUE pairs
+// for the app container. Proxies addressed via localhost are rewritten to
+// host.docker.internal (unless rewriteLocal is false, i.e. host networking),
+// since localhost inside the container is the container itself, and
+// host.docker.internal is appended to NO_PROXY so the app still reaches the
+// sandbox runtime directly. When proxies are set without any NO_PROXY, one
+// is synthesized for the same reason.
+func resolveProxyEnv(getenv func(string) string, rewriteLocal bool) []string {
+	var out []string
+	proxySeen := false
+	noProxySeen := false
+	for _, name := range proxyVarNames {
+		v := getenv(name)
+		if v == "" {
+			continue
+		}
+		if strings.EqualFold(name, "NO_PROXY") {
+			noProxySeen = true
+			out = append(out, name+"="+appendNoProxy(v))
+			continue
+		}
+		proxySeen = true
+		if rewriteLocal {
+			if rewritten, ok := rewriteLocalBaseURL(v); ok {
+				v = rewritten
+			}
+		}
+		out = append(out, name+"="+v)
+	}
+	if proxySeen && !noProxySeen {
+		out = append(out, "NO_PROXY=host.docker.internal")
+	}
+	return out
+}
+
+// appendNoProxy adds host.docker.internal to a NO_PROXY list unless it is
+// already there.
+func appendNoProxy(v string) string {
+	for _, part := range strings.Split(v, ",") {
+		if strings.TrimSpace(part) == "host.docker.internal" {
+			return v
+		}
+	}
+	return v + ",host.docker.internal"
+}
+
+// envNames extracts the variable names of KEY=VALUE pairs, for logging what
+// was forwarded without echoing values that may embed proxy credentials.
+func envNames(pairs []string) []string {
+	names := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		name, _, _ := strings.Cut(p, "=")
+		names = append(names, name)
+	}
+	return names
+}
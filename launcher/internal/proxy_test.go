@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveProxyEnv(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+	tests := []struct {
+		name         string
+		vars         map[string]string
+		rewriteLocal bool
+		want         []string
+	}{
+		{
+			name: "nothing set",
+			vars: map[string]string{},
+			want: nil,
+		},
+		{
+			name:         "uppercase proxy with localhost rewrite",
+			vars:         map[string]string{"HTTP_PROXY": "http://127.0.0.1:3128"},
+			rewriteLocal: true,
+			want: []string{
+				"HTTP_PROXY=http://host.docker.internal:3128",
+				"NO_PROXY=host.docker.internal",
+			},
+		},
+		{
+			name:         "lowercase variables are detected",
+			vars:         map[string]string{"https_proxy": "http://proxy.corp:8080", "no_proxy": ".corp"},
+			rewriteLocal: true,
+			want: []string{
+				"https_proxy=http://proxy.corp:8080",
+				"no_proxy=.corp,host.docker.internal",
+			},
+		},
+		{
+			name:         "host networking keeps localhost proxies",
+			vars:         map[string]string{"HTTP_PROXY": "http://localhost:3128"},
+			rewriteLocal: false,
+			want: []string{
+				"HTTP_PROXY=http://localhost:3128",
+				"NO_PROXY=host.docker.internal",
+			},
+		},
+		{
+			name:         "no_proxy already covering the host gateway",
+			vars:         map[string]string{"NO_PROXY": "host.docker.internal,.corp"},
+			rewriteLocal: true,
+			want:         []string{"NO_PROXY=host.docker.internal,.corp"},
+		},
+	}
+	for _, tt := range tests {
+		got := resolveProxyEnv(env(tt.vars), tt.rewriteLocal)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: resolveProxyEnv = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
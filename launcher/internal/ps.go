@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+)
+
+// CmdPs implements `openhands ps`: a table of the running instances this
+// launcher started - name, workspace, UI port, image tag, uptime and state -
+// answering "which port is this workspace on again?" without grepping
+// docker ps by hand. The workspace and tag come from the labels the launch
+// path records on every container.
+func CmdPs(args []string) int {
+	fs := flag.NewFlagSet("ps", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "emit the instance list as JSON")
+	contextName := fs.String("context", "", "docker CLI context whose instances to list")
+	fs.Parse(args)
+	if err := applyContextFlag(*contextName); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	instances, err := listInstances(ctx, cli)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if *asJSON {
+		out := make([]instanceJSON, 0, len(instances))
+		for _, c := range instances {
+			out = append(out, instanceJSONFrom(c))
+		}
+		if err := printJSON(out); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	if len(instances) == 0 {
+		fmt.Println("No OpenHands instances running.")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tWORKSPACE\tPORT\tTAG\tLIMITS\tUPTIME\tSTATE")
+	for _, c := range instances {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			containerName(c),
+			c.Labels[LabelWorkspace],
+			hostPort(c),
+			c.Labels[LabelVersion],
+			containerLimits(ctx, cli, c.ID),
+			uptime(c),
+			c.Status,
+		)
+	}
+	w.Flush()
+	return 0
+}
+
+// containerLimits renders an instance's memory and CPU caps, or "-" when it
+// runs unconstrained, so ps answers "what limits does this one have?".
+func containerLimits(ctx context.Context, cli *client.Client, id string) string {
+	insp, err := cli.ContainerInspect(ctx, id)
+	if err != nil || insp.HostConfig == nil {
+		return "-"
+	}
+	var parts []string
+	if insp.HostConfig.Memory > 0 {
+		parts = append(parts, units.BytesSize(float64(insp.HostConfig.Memory)))
+	}
+	if insp.HostConfig.NanoCPUs > 0 {
+		parts = append(parts, fmt.Sprintf("%gcpu", float64(insp.HostConfig.NanoCPUs)/1e9))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
+
+// hostPort renders the published UI port, or "-" when none is mapped.
+func hostPort(c types.Container) string {
+	for _, p := range c.Ports {
+		if p.PublicPort != 0 {
+			return fmt.Sprintf("%d", p.PublicPort)
+		}
+	}
+	// Host networking publishes no mappings; fall back to the recorded label.
+	if p := c.Labels[LabelPort]; p != "" {
+		return p
+	}
+	return "-"
+}
+
+// uptime renders how long the container has been up, coarsely.
+func uptime(c types.Container) string {
+	d := time.Since(time.Unix(c.Created, 0))
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	}
+}
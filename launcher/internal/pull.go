@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// CmdPull implements `openhands pull`: pre-fetch the app and sandbox
+// runtime images so the first real launch downloads nothing - useful on a
+// metered or slow connection, or before going offline. The same defaults
+// and tag matching as a launch apply, pull progress streams to the
+// terminal, and the summary lists each image's resolved digest so what
+// arrived can be verified. A later launch with `-pull never` then runs
+// entirely from the local images. Either pull failing exits nonzero.
+func CmdPull(args []string) int {
+	cfg := &Config{}
+	fs := flag.NewFlagSet("pull", flag.ExitOnError)
+	fs.StringVar(&cfg.Tag, "tag", DefaultTag, "OpenHands app image version")
+	fs.StringVar(&cfg.RuntimeImageOverride, "runtime-image", "", "pull this sandbox runtime image instead of the tag-matched default")
+	fs.StringVar(&cfg.Platform, "platform", "", "force the image platform (linux/amd64 or linux/arm64) for the pulls")
+	quiet := fs.Bool("quiet", false, "only print the digest summary")
+	fs.Parse(args)
+	setLogLevel(false, *quiet)
+	warnRuntimeSkew(cfg.Tag, cfg.RuntimeImageOverride)
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	refs := []string{cfg.AppImage(), cfg.RuntimeImage()}
+	for _, ref := range refs {
+		err := withRetry(ctx, "pull "+ref, func() error {
+			if *quiet {
+				return pullImageQuiet(ctx, cli, ref, cfg.Platform)
+			}
+			return pullImage(ctx, cli, ref, cfg.Platform)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	for _, ref := range refs {
+		digest := imageDigest(ctx, cli, ref)
+		if digest == "" {
+			// Locally built images carry no repo digest.
+			digest = "(no digest recorded)"
+		}
+		fmt.Printf("%s  %s\n", ref, digest)
+	}
+	return 0
+}
+
+// pullImageQuiet pulls without rendering progress; the JSON stream is still
+// decoded so pull errors are not lost with it.
+func pullImageQuiet(ctx context.Context, cli *client.Client, ref, platform string) error {
+	rc, err := cli.ImagePull(ctx, ref, types.ImagePullOptions{Platform: platform})
+	if err != nil {
+		return explainPullError(ref, err)
+	}
+	defer rc.Close()
+	dec := json.NewDecoder(rc)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return explainPullError(ref, err)
+		}
+		if msg.Error != nil {
+			return explainPullError(ref, msg.Error)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sessions are recorded as asciinema v2 cast files under
+// ~/.openhands/sessions, one per launch, replayable with `openhands play`
+// or any asciinema player.
+
+const sessionsDirName = "sessions"
+
+func sessionsDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, sessionsDirName)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// castHeader is the first line of an asciinema v2 file.
+type castHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Title     string `json:"title,omitempty"`
+}
+
+// castRecorder is an io.Writer that appends output events to a cast file.
+type castRecorder struct {
+	mu    sync.Mutex
+	f     *os.File
+	enc   *json.Encoder
+	start time.Time
+	Path  string
+}
+
+// newCastRecorder starts a recording for the given workspace.
+func newCastRecorder(workspace string, width, height int) (*castRecorder, error) {
+	dir, err := sessionsDir()
+	if err != nil {
+		return nil, err
+	}
+	name := time.Now().Format("20060102-150405") + ".cast"
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	rec := &castRecorder{
+		f:     f,
+		enc:   json.NewEncoder(f),
+		start: time.Now(),
+		Path:  f.Name(),
+	}
+	header := castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: rec.start.Unix(),
+		Title:     "OpenHands session in " + workspace,
+	}
+	if err := rec.enc.Encode(&header); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Write records one chunk of PTY output. Recording errors are swallowed so
+// a full disk cannot break the live session.
+func (r *castRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Since(r.start).Seconds()
+	_ = r.enc.Encode([]interface{}{elapsed, "o", string(p)})
+	return len(p), nil
+}
+
+func (r *castRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+// CmdPlay implements `openhands play [session]`. Without an argument the
+// most recent session is replayed; long pauses are compressed.
+func CmdPlay(args []string) int {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	maxPause := fs.Duration("max-pause", 2*time.Second, "cap on pauses between output events")
+	fs.Parse(args)
+
+	path, err := resolveSession(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	first := true
+	last := 0.0
+	for scanner.Scan() {
+		if first {
+			first = false // header line
+			continue
+		}
+		var event []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) < 3 {
+			continue
+		}
+		ts, _ := event[0].(float64)
+		kind, _ := event[1].(string)
+		data, _ := event[2].(string)
+		if kind != "o" {
+			continue
+		}
+		pause := time.Duration((ts - last) * float64(time.Second))
+		if pause > *maxPause {
+			pause = *maxPause
+		}
+		if pause > 0 {
+			time.Sleep(pause)
+		}
+		last = ts
+		os.Stdout.WriteString(data)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: reading %s: %v\n", path, err)
+		return 1
+	}
+	return 0
+}
+
+// resolveSession turns a session argument into a cast file path. It accepts
+// a full path, a session name with or without .cast, or nothing for the
+// latest recording.
+func resolveSession(arg string) (string, error) {
+	if arg != "" && filepath.IsAbs(arg) {
+		return arg, nil
+	}
+	dir, err := sessionsDir()
+	if err != nil {
+		return "", err
+	}
+	if arg != "" {
+		name := arg
+		if filepath.Ext(name) != ".cast" {
+			name += ".cast"
+		}
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("no session %q in %s", arg, dir)
+		}
+		return path, nil
+	}
+	entries, err := filepath.Glob(filepath.Join(dir, "*.cast"))
+	if err != nil || len(entries) == 0 {
+		return "", fmt.Errorf("no recorded sessions in %s", dir)
+	}
+	sort.Strings(entries)
+	return entries[len(entries)-1], nil
+}
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sensitiveEnvSuffixes marks variable names as credential-bearing by
+// convention, so env passthrough added in the future is masked by default.
+var sensitiveEnvSuffixes = []string{"_TOKEN", "_SECRET", "_KEY"}
+
+// sensitiveEnvKey reports whether an environment variable name looks like it
+// holds a credential.
+func sensitiveEnvKey(key string) bool {
+	if key == "LLM_API_KEY" {
+		return true
+	}
+	for _, suffix := range sensitiveEnvSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret hides a secret value, keeping at most the last four characters
+// so a key can still be told apart from another in a bug report.
+func maskSecret(v string) string {
+	if v == "" {
+		return ""
+	}
+	if len(v) <= 8 {
+		return secretMask
+	}
+	return secretMask + v[len(v)-4:]
+}
+
+// String renders the LLM settings with the API key masked, so %v of a
+// config can never leak it into terminals, logs or bug reports.
+func (l LLMConfig) String() string {
+	return fmt.Sprintf("{Model:%s APIKey:%s BaseURL:%s APIVersion:%s}",
+		l.Model, maskSecret(l.APIKey), l.BaseURL, l.APIVersion)
+}
+
+// String summarizes the launch-relevant configuration with secrets masked.
+func (c *Config) String() string {
+	return fmt.Sprintf("{Workspace:%s Tag:%s Port:%d Bind:%s Network:%s LLM:%s Memory:%s CPUs:%g GPUs:%s Mounts:%d Detach:%v Ephemeral:%v}",
+		c.Workspace, c.Tag, c.Port, c.Bind, c.Network, c.LLM,
+		c.Memory, c.CPUs, c.GPUs, len(c.ExtraMounts), c.Detach, c.Ephemeral)
+}
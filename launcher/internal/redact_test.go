@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestConfigStringMasksSecrets(t *testing.T) {
+	const key = "sk-proj-verysecretvalue1234"
+	cfg := &Config{
+		Workspace: "/ws",
+		Tag:       DefaultTag,
+		LLM:       LLMConfig{Model: "gpt-4o", APIKey: key},
+	}
+	for _, rendered := range []string{cfg.String(), cfg.LLM.String(), fmt.Sprintf("%v", cfg)} {
+		if strings.Contains(rendered, key) {
+			t.Errorf("rendered config leaks the API key: %q", rendered)
+		}
+	}
+	if !strings.Contains(cfg.LLM.String(), "1234") {
+		t.Errorf("LLM string should keep the last four characters for identification: %q", cfg.LLM.String())
+	}
+}
+
+func TestSensitiveEnvKey(t *testing.T) {
+	sensitive := []string{"LLM_API_KEY", "GITHUB_TOKEN", "AWS_SECRET", "DEPLOY_KEY"}
+	for _, key := range sensitive {
+		if !sensitiveEnvKey(key) {
+			t.Errorf("sensitiveEnvKey(%q) = false, want true", key)
+		}
+	}
+	harmless := []string{"LLM_MODEL", "HTTP_PROXY", "WORKSPACE_MOUNT_PATH", "TOKENIZER"}
+	for _, key := range harmless {
+		if sensitiveEnvKey(key) {
+			t.Errorf("sensitiveEnvKey(%q) = true, want false", key)
+		}
+	}
+}
+
+func TestRenderDockerCommandMasksEnv(t *testing.T) {
+	const token = "ghp_raretoken987654"
+	cfg := &Config{
+		Workspace: "/ws",
+		Tag:       DefaultTag,
+		Port:      3000,
+		Bind:      "127.0.0.1",
+		LLM:       LLMConfig{APIKey: "sk-launchsecret"},
+		env:       map[string]string{"GITHUB_TOKEN": token},
+	}
+	rendered := renderDockerCommand(cfg, false)
+	if strings.Contains(rendered, token) || strings.Contains(rendered, "sk-launchsecret") {
+		t.Errorf("dry-run output leaks a secret: %q", rendered)
+	}
+	if !strings.Contains(rendered, "GITHUB_TOKEN="+secretMask) {
+		t.Errorf("dry-run output should keep the masked variable name: %q", rendered)
+	}
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// instanceRecord is one entry in the launch registry: enough to tell the
+// user where an already-running instance lives (its URL) and which process
+// started it. The live listing in ps/stop/logs still comes from container
+// labels; the registry exists so a second launch on the same workspace can
+// be refused before a container is even created.
+type instanceRecord struct {
+	Name      string    `json:"name"`
+	Workspace string    `json:"workspace"`
+	Port      int       `json:"port"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// registryPath returns the registry file, creating its directory.
+func registryPath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "cli")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "instances.json"), nil
+}
+
+// loadRegistry reads the registry; a missing file is an empty registry.
+func loadRegistry() ([]instanceRecord, error) {
+	path, err := registryPath()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recs []instanceRecord
+	if err := json.Unmarshal(raw, &recs); err != nil {
+		// A corrupt registry heals itself on the next save; losing it only
+		// costs the duplicate-launch guard, never a running instance.
+		return nil, nil
+	}
+	return recs, nil
+}
+
+func saveRegistry(recs []instanceRecord) error {
+	path, err := registryPath()
+	if err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(recs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// registerInstance records a freshly created container, replacing any stale
+// entry under the same name.
+func registerInstance(rec instanceRecord) {
+	recs, err := loadRegistry()
+	if err != nil {
+		return
+	}
+	kept := recs[:0]
+	for _, r := range recs {
+		if r.Name != rec.Name {
+			kept = append(kept, r)
+		}
+	}
+	saveRegistry(append(kept, rec))
+}
+
+// unregisterInstance drops the entry for a container that exited or was
+// stopped. Best-effort: a crash that skips it is repaired by pruning.
+func unregisterInstance(name string) {
+	recs, err := loadRegistry()
+	if err != nil {
+		return
+	}
+	kept := recs[:0]
+	changed := false
+	for _, r := range recs {
+		if r.Name == name {
+			changed = true
+			continue
+		}
+		kept = append(kept, r)
+	}
+	if changed {
+		saveRegistry(kept)
+	}
+}
+
+// pruneRegistry drops entries whose container no longer exists, so records
+// left behind by a killed launcher process do not block future launches. It
+// returns the surviving entries.
+func pruneRegistry(ctx context.Context, cli *client.Client) []instanceRecord {
+	recs, err := loadRegistry()
+	if err != nil || len(recs) == 0 {
+		return nil
+	}
+	kept := recs[:0]
+	changed := false
+	for _, r := range recs {
+		insp, err := cli.ContainerInspect(ctx, r.Name)
+		if errdefs.IsNotFound(err) || (err == nil && insp.State != nil && !insp.State.Running) {
+			changed = true
+			continue
+		}
+		kept = append(kept, r)
+	}
+	if changed {
+		saveRegistry(kept)
+	}
+	return kept
+}
+
+// liveWorkspaceInstance returns the registered running instance on the given
+// workspace, if any, after pruning dead entries.
+func liveWorkspaceInstance(ctx context.Context, cli *client.Client, workspace string) *instanceRecord {
+	for _, r := range pruneRegistry(ctx, cli) {
+		if r.Workspace == workspace {
+			rec := r
+			return &rec
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// repoCustomization describes what the agent will pick up from the
+// workspace's .openhands directory: setup scripts and repo microagents, as
+// documented for repository-level customization.
+type repoCustomization struct {
+	SetupScript   bool
+	PreCommitHook bool
+	Microagents   []string
+	RepoConfig    bool
+	Unknown       []string
+}
+
+func (rc *repoCustomization) empty() bool {
+	return !rc.SetupScript && !rc.PreCommitHook && len(rc.Microagents) == 0 && !rc.RepoConfig
+}
+
+// inspectRepoCustomization scans workspace/.openhands. A missing directory
+// yields an empty result, not an error.
+func inspectRepoCustomization(workspace string) (*repoCustomization, error) {
+	rc := &repoCustomization{}
+	dir := filepath.Join(workspace, ".openhands")
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return rc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		switch e.Name() {
+		case "setup.sh":
+			rc.SetupScript = true
+		case "pre-commit.sh":
+			rc.PreCommitHook = true
+		case "cli.toml":
+			rc.RepoConfig = true
+		case "microagents":
+			agents, err := filepath.Glob(filepath.Join(dir, "microagents", "*.md"))
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range agents {
+				rc.Microagents = append(rc.Microagents, filepath.Base(a))
+			}
+			sort.Strings(rc.Microagents)
+		default:
+			rc.Unknown = append(rc.Unknown, e.Name())
+		}
+	}
+	sort.Strings(rc.Unknown)
+	return rc, nil
+}
+
+// reportRepoCustomization prints what was detected, so users know their repo
+// instructions are actually reaching the agent. The directory rides along
+// inside the workspace mount, so nothing extra needs forwarding.
+func reportRepoCustomization(w io.Writer, rc *repoCustomization) {
+	if rc.empty() {
+		return
+	}
+	fmt.Fprintln(w, "Repository customization detected in .openhands/:")
+	if rc.SetupScript {
+		fmt.Fprintln(w, "  - setup.sh (runs when the agent session starts)")
+	}
+	if rc.PreCommitHook {
+		fmt.Fprintln(w, "  - pre-commit.sh (installed as a git pre-commit hook in the sandbox)")
+	}
+	if len(rc.Microagents) > 0 {
+		fmt.Fprintf(w, "  - microagents: %s\n", strings.Join(rc.Microagents, ", "))
+	}
+	if rc.RepoConfig {
+		fmt.Fprintln(w, "  - cli.toml (launcher defaults for this repository)")
+	}
+	if len(rc.Unknown) > 0 {
+		fmt.Fprintf(w, "  - ignored entries the agent does not know about: %s\n", strings.Join(rc.Unknown, ", "))
+	}
+}
@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/errdefs"
+)
+
+// retryAttempts bounds how often an operation is retried before its error is
+// surfaced. Combined with doubling backoff this keeps the worst case under
+// half a minute.
+const retryAttempts = 4
+
+// withRetry runs fn, retrying transient failures with jittered exponential
+// backoff. Permanent errors (bad image name, auth failures, ...) are returned
+// immediately so the user is not kept waiting on something that cannot
+// succeed.
+func withRetry(ctx context.Context, what string, fn func() error) error {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt == retryAttempts || !isTransient(err) {
+			return err
+		}
+		// Add up to 50% jitter so parallel launches don't retry in
+		// lockstep against the same registry.
+		delay := backoff + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		fmt.Fprintf(os.Stderr, "%s failed (attempt %d/%d), retrying in %s: %v\n",
+			what, attempt, retryAttempts, delay.Round(100*time.Millisecond), err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransient reports whether an error is likely to go away on its own:
+// registry 5xx responses, DNS or connection blips, and the containerd race
+// where a just-removed container name is briefly still reserved.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsNotFound
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errdefs.IsUnavailable(err) || errdefs.IsDeadline(err) {
+		return true
+	}
+	// The daemon flattens registry responses into message strings, so the
+	// status code is only available textually.
+	msg := err.Error()
+	for _, needle := range []string{
+		"500 Internal Server Error",
+		"502 Bad Gateway",
+		"503 Service Unavailable",
+		"504 Gateway Timeout",
+		"TLS handshake timeout",
+		"connection reset by peer",
+		"is already in use by container", // containerd remove/create race
+	} {
+		if strings.Contains(msg, needle) {
+			return true
+		}
+	}
+	return false
+}
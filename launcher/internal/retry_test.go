@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain", errors.New("manifest unknown"), false},
+		{"registry 503", errors.New("received unexpected HTTP status: 503 Service Unavailable"), true},
+		{"dns", &net.DNSError{IsNotFound: true}, true},
+		{"conn reset", syscall.ECONNRESET, true},
+		{"name race", errors.New("Conflict. The container name \"/openhands-app\" is already in use by container \"abc\""), true},
+		{"auth", errors.New("pull access denied, repository does not exist or may require authorization"), false},
+	}
+	for _, tc := range cases {
+		if got := isTransient(tc.err); got != tc.want {
+			t.Errorf("%s: isTransient = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestWithRetryStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), "op", func() error {
+		calls++
+		return errors.New("manifest unknown")
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("calls = %d, err = %v; want 1 call and an error", calls, err)
+	}
+}
+
+func TestWithRetryRetriesTransientError(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), "op", func() error {
+		calls++
+		if calls < 2 {
+			return syscall.ECONNRESET
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Fatalf("calls = %d, err = %v; want success on second call", calls, err)
+	}
+}
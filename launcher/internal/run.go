@@ -0,0 +1,587 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+)
+
+// Run launches an OpenHands instance with the given configuration and blocks
+// until the app container exits.
+func Run(cfg *Config) error {
+	ctx := context.Background()
+	if cfg.Ephemeral && !cfg.DryRun {
+		original := cfg.Workspace
+		copyPath, err := prepareEphemeral(original)
+		if err != nil {
+			return err
+		}
+		cfg.Workspace = copyPath
+		defer func() {
+			if err := finishEphemeral(original, copyPath); err != nil {
+				fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			}
+		}()
+	}
+	if err := cfg.LoadSecrets(); err != nil {
+		return err
+	}
+	if err := cfg.ResolveSecretRefs(); err != nil {
+		return err
+	}
+	if err := checkDockerArgs(cfg); err != nil {
+		return err
+	}
+	if cfg.DryRun {
+		fmt.Println(renderDockerCommand(cfg, cfg.ShowSecrets))
+		return nil
+	}
+	if err := maybeRunWizard(cfg); err != nil {
+		return err
+	}
+	if logF := openLaunchLog(cfg); logF != nil {
+		defer logF.Close()
+		cfg.logWriter = logF
+	}
+	// Config dump and full argv only at debug level, and always with
+	// secrets masked; see Config.String.
+	debugf("config: %s", cfg)
+	debugf("docker command: %s", renderDockerCommand(cfg, false))
+	startUpdateCheck(cfg)
+	// The reporter is finished explicitly before the final URL; the defer
+	// covers every error return so the spinner never bleeds into an error
+	// message.
+	cfg.stages = startStages(cfg)
+	defer cfg.stages.Finish()
+	cfg.stages.Stage("checking docker")
+	cli, err := NewDockerClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	if !cfg.SkipPreflight {
+		if err := PingDaemon(ctx, cli); err != nil {
+			return err
+		}
+	}
+	if cfg.GPUs != "" {
+		if err := checkGPURuntime(ctx, cli); err != nil {
+			return err
+		}
+	}
+	if err := checkNetwork(ctx, cli, cfg.Network); err != nil {
+		return err
+	}
+	custom, err := inspectRepoCustomization(cfg.Workspace)
+	if err != nil {
+		return err
+	}
+	reportRepoCustomization(os.Stderr, custom)
+	if cfg.RemoteWorkspace == "" {
+		// A remote workspace lives on the daemon's host; local checks do not
+		// apply to it.
+		preflightWorkspace(cfg.Workspace)
+	}
+	// Two agents writing to the same checkout is never what the user meant,
+	// so a second launch on an already-served workspace is refused up front.
+	// A live instance under the same name is handled below by attaching or
+	// -recreate, with a better message than the registry refusal.
+	if rec := liveWorkspaceInstance(ctx, cli, cfg.Workspace); rec != nil && !cfg.Force && rec.Name != cfg.containerName() {
+		return fmt.Errorf("an instance is already running on %s at http://localhost:%d (container %s); use -force to start another anyway",
+			rec.Workspace, rec.Port, rec.Name)
+	}
+	// The port is settled before the pulls below, so a conflict costs
+	// milliseconds, not a wasted multi-GB download. From here on cfg.Port is
+	// final: the publish spec, the UI URL and the registry entry all use it.
+	if cfg.Network != "host" {
+		if err := resolvePort(cfg); err != nil {
+			return err
+		}
+	}
+	if cfg.Scan {
+		if err := ScanImages(cfg, cfg.AcceptRisk); err != nil {
+			return err
+		}
+	}
+	// Both images are fetched up front so the readiness timeout does not
+	// start ticking while a multi-GB pull is still running, and so the
+	// server never has to pull the runtime image mid-conversation.
+	refs := []string{cfg.AppImage()}
+	if !cfg.NoDockerSock {
+		// The runtime image is never started in local-runtime mode.
+		refs = append(refs, cfg.RuntimeImage())
+	}
+	for _, ref := range refs {
+		if _, _, err := cli.ImageInspectWithRaw(ctx, ref); err != nil || cfg.Pull == "always" {
+			// The pull renders its own progress line, so the spinner is
+			// blanked for its duration; non-terminal output still gets the
+			// timestamped stage.
+			cfg.stages.Stage("pulling image %s", ref)
+			cfg.stages.Clear()
+		}
+		if err := EnsureImagePolicy(ctx, cli, ref, cfg.Pull, cfg.Platform); err != nil {
+			return err
+		}
+	}
+	warnImagePlatforms(ctx, cli, cfg)
+	if cfg.VerifyDigest {
+		if err := verifyImageDigests(ctx, cli, cfg); err != nil {
+			return err
+		}
+	}
+	defer cleanupLLMKeyFile(cfg)
+	cfg.stages.Stage("starting container")
+	id, err := reuseOrCreateContainer(ctx, cli, cfg)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		// An existing instance under this name was attached to instead.
+		return nil
+	}
+	auditLaunch(cfg, imageDigest(ctx, cli, cfg.AppImage()))
+	registerInstance(instanceRecord{
+		Name:      cfg.containerName(),
+		Workspace: cfg.Workspace,
+		Port:      cfg.Port,
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+	})
+	if cfg.Detach {
+		// A detached instance outlives this process; its entry is removed by
+		// `openhands stop` or by pruning once the container is gone.
+		return runDetached(ctx, cli, cfg, id)
+	}
+	// The Ctrl-C path also ends up here: the signal handler stops the
+	// container, the wait below returns, and the deferred unregister runs.
+	defer unregisterInstance(cfg.containerName())
+	for attempt := 0; ; attempt++ {
+		crashed, err := superviseContainer(ctx, cli, cfg, id, attempt == 0)
+		if !crashed || attempt >= cfg.RestartOnCrash {
+			return err
+		}
+		delay := restartBackoff(attempt)
+		warnf("Restarting after the crash in %s (attempt %d of %d)...", delay, attempt+1, cfg.RestartOnCrash)
+		time.Sleep(delay)
+		// The replacement keeps the name and port, so the registry entry and
+		// any open browser tab stay valid; only the start time moves.
+		id, err = CreateContainer(ctx, cli, cfg)
+		if err != nil {
+			return fmt.Errorf("recreating the container after a crash: %w", err)
+		}
+		registerInstance(instanceRecord{
+			Name:      cfg.containerName(),
+			Workspace: cfg.Workspace,
+			Port:      cfg.Port,
+			PID:       os.Getpid(),
+			StartedAt: time.Now(),
+		})
+	}
+}
+
+// restartBackoff spaces -restart-on-crash attempts out: 2s, 4s, 8s... capped
+// at half a minute, so a crash-looping server does not spin.
+func restartBackoff(attempt int) time.Duration {
+	delay := 2 * time.Second << attempt
+	if delay > 30*time.Second {
+		return 30 * time.Second
+	}
+	return delay
+}
+
+// superviseContainer starts the container and watches it until it exits. It
+// reports whether the exit was a crash - an exit nobody asked for - so the
+// caller can decide about a restart; user-initiated stops, startup failures
+// and finished task runs never count as crashes.
+func superviseContainer(ctx context.Context, cli *client.Client, cfg *Config, id string, firstRun bool) (crashed bool, runErr error) {
+	waitCh, errCh, err := StartContainer(ctx, cli, cfg, id)
+	if err != nil {
+		return false, err
+	}
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	go watchResourcePressure(watchCtx, cli, id)
+	shutdown := handleShutdownSignals(watchCtx, cli, id)
+	if cfg.Task != "" && cfg.TaskTimeout > 0 {
+		// The hard cap for CI: a wedged task is stopped and the container's
+		// exit status is surfaced like any other failure.
+		go func() {
+			select {
+			case <-watchCtx.Done():
+			case <-time.After(cfg.TaskTimeout):
+				warnf("task exceeded the %s timeout; stopping the container", cfg.TaskTimeout)
+				grace := 15
+				if err := cli.ContainerStop(context.Background(), id, container.StopOptions{Timeout: &grace}); err != nil {
+					errorf("stopping the timed-out container: %v", err)
+				}
+			}
+		}()
+	}
+
+	// The base URL hosts the readiness endpoints; the open URL adds the
+	// -open-path route and is what gets printed and opened.
+	url := cfg.uiURL()
+	open := cfg.openURL()
+	// A readiness failure stops the container, which makes the wait below
+	// return; the recorded error then wins over the container's own exit
+	// status so the user sees "startup failed" and not just an exit code.
+	startupFailed := make(chan error, 1)
+	go func() {
+		if cfg.Task != "" {
+			// Headless task runs serve no UI; there is nothing to
+			// poll or open.
+			return
+		}
+		cfg.stages.Stage("waiting for server on :%d", cfg.Port)
+		if err := waitReady(ctx, cli, id, url, cfg.OpenPath, cfg.StartupTimeout); err != nil {
+			cfg.stages.Finish()
+			errorf("startup failed: %v", err)
+			startupFailed <- err
+			stopStartupFailure(cli, cfg, id)
+			return
+		}
+		if firstRun {
+			cfg.stages.Stage("opening browser")
+		}
+		cfg.stages.Finish()
+		if quietMode() {
+			// The URL is the one piece of output scripts still need.
+			if firstRun {
+				fmt.Println(open)
+			}
+		} else {
+			infof("OpenHands is running at %s", open)
+		}
+		if !firstRun {
+			// A restarted server reuses the port, so the existing browser
+			// tab recovers on reload; do not open another one.
+			return
+		}
+		if cfg.CopyURL {
+			if err := copyToClipboard(open); err != nil {
+				warnf("could not copy the URL: %v", err)
+			} else {
+				infof("URL copied to the clipboard.")
+			}
+		}
+		openBrowser(open, cfg.Browser)
+		if cfg.OpenVSCode {
+			vs, err := waitVSCodeURL(watchCtx, cli, cfg, url)
+			if err != nil {
+				warnf("could not open VS Code: %v", err)
+				return
+			}
+			if quietMode() {
+				fmt.Println(vs)
+			} else {
+				infof("VS Code is available at %s", vs)
+			}
+			openBrowser(vs, cfg.Browser)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return false, fmt.Errorf("waiting for container: %w", err)
+	case status := <-waitCh:
+		auditExit(cfg, status.StatusCode)
+		select {
+		case err := <-startupFailed:
+			return false, fmt.Errorf("startup failed: %w", err)
+		default:
+		}
+		if status.Error != nil {
+			return false, fmt.Errorf("container exited with error: %s", status.Error.Message)
+		}
+		if shutdown.forced {
+			// Mirror what the kernel reports for SIGKILL.
+			return false, &exitCodeError{code: 137, msg: "container was killed after the graceful stop did not finish"}
+		}
+		if status.StatusCode != 0 {
+			err := &exitCodeError{
+				code: int(status.StatusCode),
+				msg:  fmt.Sprintf("container exited with status %d", status.StatusCode),
+			}
+			if cfg.Task == "" && !shutdown.requested {
+				reportCrash(ctx, cli, id, int(status.StatusCode))
+				return true, err
+			}
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// reportCrash prints the banner for a server that died on its own: the exit
+// status plus the last lines of its output, which usually contain the panic
+// or the OOM kill.
+func reportCrash(ctx context.Context, cli *client.Client, id string, code int) {
+	restoreTerminalState()
+	errorf("OpenHands exited unexpectedly with status %d", code)
+	printLogTail(ctx, cli, id, "50")
+}
+
+// handleShutdownSignals makes Ctrl-C stop the container instead of just
+// killing the client, which used to leave the server and its sandbox running
+// in the background. The first signal asks for a graceful docker stop so the
+// server can shut its conversations down; a second escalates to kill. The
+// returned flag reports whether the kill path was taken, so the exit status
+// can reflect a forced shutdown.
+func handleShutdownSignals(ctx context.Context, cli *client.Client, id string) *shutdownFlags {
+	flags := new(shutdownFlags)
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(sigCh)
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+		}
+		flags.requested = true
+		restoreTerminalState()
+		fmt.Fprintln(os.Stderr, "\nStopping OpenHands... (Ctrl-C again to kill)")
+		go func() {
+			grace := 15
+			if err := cli.ContainerStop(context.Background(), id, container.StopOptions{Timeout: &grace}); err != nil {
+				fmt.Fprintf(os.Stderr, "openhands: stopping container: %v\n", err)
+			}
+		}()
+		select {
+		case <-ctx.Done():
+		case <-sigCh:
+			flags.forced = true
+			fmt.Fprintln(os.Stderr, "Killing the container.")
+			if err := cli.ContainerKill(context.Background(), id, "KILL"); err != nil {
+				fmt.Fprintf(os.Stderr, "openhands: killing container: %v\n", err)
+			}
+		}
+	}()
+	return flags
+}
+
+// shutdownFlags records how a shutdown came about: requested is set on the
+// first Ctrl-C (so the exit is not mistaken for a crash), forced when a
+// second one escalated to kill. Both are only read after the container wait
+// returns, which the stop itself causes, so no further synchronization is
+// needed.
+type shutdownFlags struct {
+	requested bool
+	forced    bool
+}
+
+// runDetached starts the container without attaching, waits for readiness,
+// prints the handle and returns while the container keeps running. A
+// container that dies before the server comes up fails the command and shows
+// the log tail, so scripts get a real exit code instead of a dead instance.
+func runDetached(ctx context.Context, cli *client.Client, cfg *Config, id string) error {
+	err := withRetry(ctx, "start container", func() error {
+		return cli.ContainerStart(ctx, id, types.ContainerStartOptions{})
+	})
+	if err != nil {
+		return fmt.Errorf("starting container: %w", err)
+	}
+	url := cfg.uiURL()
+	open := cfg.openURL()
+	if cfg.Task == "" {
+		cfg.stages.Stage("waiting for server on :%d", cfg.Port)
+		if err := waitReady(ctx, cli, id, url, cfg.OpenPath, cfg.StartupTimeout); err != nil {
+			cfg.stages.Finish()
+			stopStartupFailure(cli, cfg, id)
+			return fmt.Errorf("startup failed: %w", err)
+		}
+	}
+	cfg.stages.Finish()
+	if quietMode() {
+		fmt.Println(open)
+	} else {
+		fmt.Printf("OpenHands is running at %s (container %s)\n", open, id[:12])
+		fmt.Println("Stop it with `openhands stop` when you are done.")
+	}
+	return nil
+}
+
+// printLogTail shows the container's most recent output, used to give failed
+// background launches some context.
+func printLogTail(ctx context.Context, cli *client.Client, id, tail string) {
+	rc, err := ContainerLogs(ctx, cli, id, tail)
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+	fmt.Fprintf(os.Stderr, "--- last container output ---\n")
+	io.Copy(os.Stderr, rc)
+}
+
+// waitReady waits for the instance to become usable. When the image defines
+// a HEALTHCHECK the container's own health status is authoritative, since it
+// runs inside the container and keeps working when host-to-container
+// networking is what's actually broken. Images without a healthcheck fall
+// back to HTTP polling from the host. The page probed is baseURL plus the
+// -open-path route, so the page about to be opened is the one known ready.
+func waitReady(ctx context.Context, cli *client.Client, id, baseURL, openPath string, timeout time.Duration) error {
+	if openPath == "" {
+		openPath = "/"
+	}
+	insp, err := cli.ContainerInspect(ctx, id)
+	if err == nil && insp.State != nil && insp.State.Health != nil {
+		err = pollHealth(ctx, cli, id, baseURL, openPath, timeout)
+	} else {
+		err = pollPage(ctx, cli, id, baseURL, openPath, timeout)
+	}
+	if err != nil {
+		// The container's own output says why it never came up.
+		printLogTail(ctx, cli, id, "50")
+	}
+	return err
+}
+
+// stopStartupFailure tears down a container whose server never came up, so a
+// failed launch does not leave everything running in the background. The log
+// tail has already been shown by waitReady; AutoRemove then cleans the
+// container up unless -keep-container asked for a post-mortem.
+func stopStartupFailure(cli *client.Client, cfg *Config, id string) {
+	grace := 15
+	if err := cli.ContainerStop(context.Background(), id, container.StopOptions{Timeout: &grace}); err != nil {
+		errorf("stopping the failed container: %v", err)
+		return
+	}
+	if cfg.KeepContainer {
+		infof("The container was kept for inspection: `docker logs %s`", id[:12])
+	}
+}
+
+// pollHealth follows the container's health status until it settles, and
+// also notices the container dying outright so the user gets an exit code
+// instead of a readiness timeout.
+func pollHealth(ctx context.Context, cli *client.Client, id, baseURL, openPath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		insp, err := cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return fmt.Errorf("inspecting container: %w", err)
+		}
+		if insp.State == nil {
+			return fmt.Errorf("container has no state")
+		}
+		if !insp.State.Running {
+			return fmt.Errorf("container exited with code %d before becoming ready; see `docker logs %s`", insp.State.ExitCode, id[:12])
+		}
+		if insp.State.Health != nil {
+			switch insp.State.Health.Status {
+			case types.Healthy:
+				if !probeEndpoint(baseURL + openPath) {
+					warnf("Container is healthy but %s is unreachable from the host; check the port mapping or a proxy in between.", baseURL)
+				}
+				return nil
+			case types.Unhealthy:
+				return fmt.Errorf("container healthcheck reports unhealthy; see `docker logs %s`", id[:12])
+			}
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("container did not report healthy within %s", timeout)
+}
+
+// apiProbePath is the backend endpoint used for readiness, with /health as
+// the preferred probe on newer images. The static UI can be served while the
+// Python backend is still starting, so both are probed.
+const (
+	apiProbePath    = "/api/options/config"
+	healthProbePath = "/health"
+)
+
+// probeClient bounds every readiness request; the default client has no
+// timeout and a wedged server would hang the poll loop forever.
+var probeClient = &http.Client{Timeout: 3 * time.Second}
+
+// pollPage polls the UI root and the backend API until both respond or the
+// timeout elapses, backing off exponentially between rounds. Progress is
+// reported as the two endpoints come up, so a half-working instance ("UI
+// served but API not responding") is distinguishable from one where nothing
+// is listening. A container that exits during the wait aborts immediately
+// with its status instead of burning the remaining timeout.
+func pollPage(ctx context.Context, cli *client.Client, id, baseURL, openPath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	backoff := 500 * time.Millisecond
+	var uiUp, apiUp, reportedUI bool
+	for time.Now().Before(deadline) {
+		if insp, err := cli.ContainerInspect(ctx, id); err == nil && insp.State != nil && !insp.State.Running {
+			return fmt.Errorf("container exited with code %d before becoming ready", insp.State.ExitCode)
+		}
+		type result struct {
+			api bool
+			ok  bool
+		}
+		results := make(chan result, 2)
+		go func() { results <- result{api: false, ok: probeEndpoint(baseURL + openPath)} }()
+		go func() {
+			// /health is cheaper and unambiguous where available; fall
+			// back to the options endpoint on older images.
+			ok := probeEndpoint(baseURL + healthProbePath)
+			if !ok {
+				ok = probeEndpoint(baseURL + apiProbePath)
+			}
+			results <- result{api: true, ok: ok}
+		}()
+		for i := 0; i < 2; i++ {
+			r := <-results
+			if r.api {
+				apiUp = r.ok
+			} else {
+				uiUp = r.ok
+			}
+		}
+		if uiUp && apiUp {
+			return nil
+		}
+		if uiUp && !reportedUI {
+			infof("UI is served; waiting for the backend API...")
+			reportedUI = true
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < 5*time.Second {
+			backoff *= 2
+		}
+	}
+	switch {
+	case uiUp && !apiUp:
+		return fmt.Errorf("UI served but API not responding at %s after %s; the server may have failed to start", baseURL, timeout)
+	case !uiUp && apiUp:
+		return fmt.Errorf("API responding but UI not served at %s after %s; check the frontend build or the proxy", baseURL, timeout)
+	default:
+		return fmt.Errorf("nothing listening at %s after %s; check the port mapping and container logs", baseURL, timeout)
+	}
+}
+
+// probeEndpoint reports whether the URL answers like a live server:
+// redirects and auth challenges count as up (something is handling
+// requests), server errors do not.
+func probeEndpoint(url string) bool {
+	resp, err := probeClient.Get(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return probeStatusUp(resp.StatusCode)
+}
+
+// probeStatusUp classifies a probe response code: 2xx/3xx and 401 mean the
+// server is handling requests, everything else (notably 5xx while booting)
+// means not yet.
+func probeStatusUp(status int) bool {
+	return (status >= 200 && status < 400) || status == http.StatusUnauthorized
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProbeStatusUp(t *testing.T) {
+	up := []int{200, 204, 301, 307, 401}
+	for _, s := range up {
+		if !probeStatusUp(s) {
+			t.Errorf("status %d should count as up", s)
+		}
+	}
+	down := []int{404, 500, 502, 503}
+	for _, s := range down {
+		if probeStatusUp(s) {
+			t.Errorf("status %d should count as not ready", s)
+		}
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 2 * time.Second},
+		{1, 4 * time.Second},
+		{2, 8 * time.Second},
+		{3, 16 * time.Second},
+		{4, 30 * time.Second},
+		{10, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := restartBackoff(tt.attempt); got != tt.want {
+			t.Errorf("restartBackoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
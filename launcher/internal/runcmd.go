@@ -0,0 +1,246 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// RegisterLaunchFlags declares the flags shared by the default launch
+// invocation and `openhands run` on the given FlagSet.
+func RegisterLaunchFlags(fs *flag.FlagSet, cfg *Config) {
+	fs.StringVar(&cfg.ConfigFile, "config", "", "config file to use instead of ~/.openhands/cli.toml")
+	fs.StringVar(&cfg.Tag, "tag", DefaultTag, "OpenHands app image version")
+	fs.IntVar(&cfg.Port, "port", DefaultPort, "host port to serve the UI on")
+	fs.StringVar(&cfg.Bind, "bind", "127.0.0.1", "host address to publish the UI on (0.0.0.0 exposes it to the network)")
+	fs.StringVar(&cfg.LLM.Model, "model", "", "LLM model the agent should use, e.g. anthropic/claude-sonnet-4-20250514")
+	fs.StringVar(&cfg.LLM.APIKey, "api-key", os.Getenv("LLM_API_KEY"), "LLM API key (defaults to $LLM_API_KEY)")
+	fs.StringVar(&cfg.LLM.BaseURL, "llm-base-url", os.Getenv("LLM_BASE_URL"), "OpenAI-compatible endpoint for proxied or local models (defaults to $LLM_BASE_URL)")
+	fs.StringVar(&cfg.LLM.APIVersion, "llm-api-version", os.Getenv("LLM_API_VERSION"), "LLM API version, for Azure OpenAI (defaults to $LLM_API_VERSION)")
+	fs.StringVar(&cfg.SecretsFile, "secrets-file", "", "dotenv file whose variables are injected only into the sandbox runtime")
+	fs.StringVar(&cfg.ServerConfig, "server-config", "", "backend config.toml to mount into the app container")
+	fs.Var(&envMapFlag{&cfg.SandboxEnv}, "sandbox-env", "KEY=VALUE exported inside the sandbox runtime (repeatable)")
+	fs.Var((*multiFlag)(&cfg.EnvVars), "e", "KEY=VALUE set on the app container itself; a bare KEY inherits the shell's value (repeatable)")
+	fs.Var((*multiFlag)(&cfg.EnvVars), "env", "alias for -e")
+	fs.StringVar(&cfg.EnvFile, "env-file", "", "dotenv file of variables set on the app container")
+	fs.Var((*multiFlag)(&cfg.ForwardEnvPrefixes), "forward-env-prefix", "additional env var prefix forwarded from the shell, beyond LLM_/SANDBOX_/AGENT_/SECURITY_ (repeatable)")
+	fs.BoolVar(&cfg.NoEnvForward, "no-env-forward", false, "do not forward LLM_/SANDBOX_/AGENT_/SECURITY_ env vars from the shell")
+	fs.Var((*multiFlag)(&cfg.ExtraMounts), "mount", "additional bind mount as SRC:DST[:ro|rw], e.g. ~/data:/data:ro (repeatable)")
+	fs.Var((*multiFlag)(&cfg.DockerArgs), "docker-arg", "extra docker run option for the app container, e.g. --tmpfs=/scratch (repeatable; also accepted after --)")
+	fs.BoolVar(&cfg.LegacyMounts, "legacy-mounts", false, "use the pre-SANDBOX_VOLUMES workspace mounting scheme, for custom tags built from an old release")
+	fs.BoolVar(&cfg.ReadOnly, "read-only", false, "mount the workspace read-only, so the agent can analyze the code but not modify it")
+	fs.IntVar(&cfg.RestartOnCrash, "restart-on-crash", 0, "restart a crashed server up to N times with backoff, keeping the name and port (0 disables)")
+	fs.BoolVar(&cfg.Force, "force", false, "allow defaulting the workspace to / or the home directory")
+	fs.BoolVar(&cfg.CreateWorkspace, "create-workspace", false, "create the workspace directory if it does not exist")
+	fs.BoolVar(&cfg.NoInteractive, "no-interactive", false, "never prompt; warn instead of running the first-run setup wizard")
+	fs.StringVar(&cfg.User, "user", "", "uid[:gid] to run as, when the invoking user's UID is not right (NFS, sudo)")
+	fs.StringVar(&cfg.Timezone, "tz", "", "timezone for the containers (defaults to the host's)")
+	fs.StringVar(&cfg.ShmSize, "shm-size", "", "/dev/shm size for the app container, e.g. 2g (browser automation needs more than the 64MB default)")
+	fs.Var((*multiFlag)(&cfg.Ulimits), "ulimit", "container ulimit as name=soft[:hard], e.g. nofile=65535:65535 (repeatable)")
+	fs.Var((*multiFlag)(&cfg.SecurityOpts), "security-opt", "container security option, e.g. seccomp=profile.json or apparmor=openhands (repeatable)")
+	fs.BoolVar(&cfg.Hardened, "hardened", false, "run with all capabilities dropped except the minimal set the server needs, plus no-new-privileges")
+	fs.BoolVar(&cfg.Scan, "scan", false, "scan the app and runtime images with trivy or grype before first use of a tag")
+	fs.BoolVar(&cfg.AcceptRisk, "accept-risk", false, "proceed even when -scan reports critical vulnerabilities")
+	fs.Var((*multiFlag)(&cfg.EnableExperiments), "enable-experiment", "turn on a named experimental behavior (repeatable)")
+	fs.BoolVar(&cfg.CopyURL, "copy-url", false, "copy the UI URL to the clipboard once the server is ready")
+	fs.BoolVar(&cfg.Ephemeral, "ephemeral", false, "run against a temporary copy of the workspace and review the changes at the end")
+	fs.BoolVar(&cfg.Detach, "d", false, "detach once the server is ready, leaving the container running")
+	fs.BoolVar(&cfg.Detach, "detach", false, "alias for -d")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "print the equivalent docker run command and exit without launching")
+	fs.BoolVar(&cfg.ShowSecrets, "show-secrets", false, "leave secret values unmasked in -dry-run output")
+	fs.BoolVar(&cfg.Verbose, "v", false, "print debug-level messages, including the full docker command (secrets masked)")
+	fs.BoolVar(&cfg.Verbose, "verbose", false, "alias for -v")
+	fs.BoolVar(&cfg.Quiet, "q", false, "only print warnings and errors; the UI URL becomes the sole stdout output")
+	fs.BoolVar(&cfg.Quiet, "quiet", false, "alias for -q")
+	fs.StringVar(&cfg.LogFile, "log-file", "", "write the launch log here instead of ~/.openhands/logs/cli-<timestamp>.log")
+	fs.BoolVar(&cfg.NoLogFile, "no-log-file", false, "do not write a launch log file")
+	fs.IntVar(&cfg.LogRetention, "log-retention", defaultLogRetention, "how many launch logs to keep before the oldest are deleted")
+	fs.BoolVar(&cfg.NoProxyPassthrough, "no-proxy-passthrough", false, "do not forward the shell's HTTP_PROXY/HTTPS_PROXY/NO_PROXY into the container")
+	fs.StringVar(&cfg.Network, "network", "", "docker network to attach the app container to; \"host\" uses host networking")
+	fs.StringVar(&cfg.Memory, "memory", "", "memory limit for the app container in docker units, e.g. 4g (default unlimited)")
+	fs.Float64Var(&cfg.CPUs, "cpus", 0, "CPU limit for the app container as a core count, e.g. 2.5 (default unlimited)")
+	fs.StringVar(&cfg.GPUs, "gpus", "", "expose host GPUs to the containers, docker-style: all, a count, or device=ID,... (needs the NVIDIA Container Toolkit)")
+	fs.StringVar(&cfg.Platform, "platform", "", "force the image platform (linux/amd64 or linux/arm64) for pulls and the container; emulation is slow")
+	fs.StringVar(&cfg.DockerSocket, "docker-socket", "", "engine socket path to use and mount, when detection picks the wrong one")
+	fs.StringVar(&cfg.Context, "context", "", "docker CLI context to run the containers on (a tcp context targets a remote daemon)")
+	fs.StringVar(&cfg.RemoteWorkspace, "remote-workspace", "", "workspace path on the remote daemon's host, required with a remote -context")
+	fs.DurationVar(&cfg.StartupTimeout, "startup-timeout", 3*time.Minute, "how long to wait for the server to become ready before giving up and stopping the container")
+	fs.BoolVar(&cfg.PortRetry, "port-retry", false, "when the requested port is busy, fall back to a free one instead of failing")
+	fs.BoolVar(&cfg.NoDockerSock, "no-docker-sock", false, "do not hand the engine socket to the app container; the agent runs inside it via the local runtime (no container-level sandbox isolation, but the agent cannot control the daemon)")
+	fs.StringVar(&cfg.StateDir, "state-dir", "", "host directory holding server state - conversations, settings (defaults to ~/.openhands)")
+	fs.StringVar(&cfg.Name, "name", "", "stable container name, so the instance can be addressed by stop, logs and ps")
+	fs.BoolVar(&cfg.Recreate, "recreate", false, "replace an existing container with the same name instead of attaching to it")
+	fs.BoolVar(&cfg.Resume, "resume", false, "start the existing stopped container with this -name instead of creating a new one")
+	fs.BoolVar(&cfg.KeepContainer, "keep-container", false, "do not remove the app container after it exits, for post-mortem debugging")
+	fs.BoolVar(&cfg.InsecureEnvSecrets, "insecure-env-secrets", false, "pass the LLM API key as a plain container env var instead of a mounted key file")
+	fs.StringVar(&cfg.Pull, "pull", "missing", "image pull policy: always, missing or never")
+	fs.BoolVar(&cfg.VerifyDigest, "verify-digest", false, "resolve and print the digest each tag-based image runs as")
+	fs.StringVar(&cfg.ExpectedDigest, "expected-digest", "", "refuse to start unless the app image resolves to this sha256:... digest")
+	fs.BoolVar(&cfg.NoUpdateCheck, "no-update-check", false, "skip the startup check for newer releases")
+	fs.BoolVar(&cfg.SkipPreflight, "skip-preflight", false, "skip the daemon reachability probe before launching")
+	fs.StringVar(&cfg.Browser, "browser", "", "command to open the UI URL with (defaults to $BROWSER, then the platform opener)")
+	fs.StringVar(&cfg.OpenPath, "open-path", "", "route appended to the UI URL when polling readiness and opening the browser, e.g. /settings")
+	fs.BoolVar(&cfg.OpenVSCode, "open-vscode", false, "also open the runtime's embedded VS Code server once the app is up")
+}
+
+// envMapFlag parses repeatable KEY=VALUE flags into a map.
+type envMapFlag struct {
+	m *map[string]string
+}
+
+func (e *envMapFlag) String() string {
+	if e.m == nil || *e.m == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(*e.m))
+	for _, k := range sortedKeys(*e.m) {
+		pairs = append(pairs, k+"="+(*e.m)[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (e *envMapFlag) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", v)
+	}
+	if *e.m == nil {
+		*e.m = make(map[string]string)
+	}
+	(*e.m)[key] = value
+	return nil
+}
+
+// FinalizeConfig validates the workspace, merges config files under the
+// explicitly set flags and re-validates the result. It is the single place
+// where flag, repo and global configuration meet.
+func FinalizeConfig(cfg *Config, fs *flag.FlagSet) error {
+	if err := ValidateFlags(cfg); err != nil {
+		return err
+	}
+	setFlags := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	// $LLM_API_KEY counts as explicitly set: the precedence is flags >
+	// environment > config files.
+	if !setFlags["api-key"] && os.Getenv("LLM_API_KEY") != "" {
+		setFlags["api-key"] = true
+	}
+	if !setFlags["llm-base-url"] && os.Getenv("LLM_BASE_URL") != "" {
+		setFlags["llm-base-url"] = true
+	}
+	var fileCfg *FileConfig
+	var repoCfgPath string
+	var err error
+	if cfg.ConfigFile != "" {
+		fileCfg, repoCfgPath, err = LoadMergedConfigAt(cfg.ConfigFile, cfg.Workspace)
+	} else {
+		fileCfg, repoCfgPath, err = LoadMergedConfig(cfg.Workspace)
+	}
+	if err != nil {
+		return err
+	}
+	if repoCfgPath != "" {
+		infof("Using repository config %s", repoCfgPath)
+	}
+	fileCfg.ApplyTo(cfg, func(name string) bool { return setFlags[name] })
+	// With no flag, environment or config value, fall back to the key
+	// stored by `openhands auth login`.
+	if cfg.LLM.APIKey == "" {
+		if key, source := lookupAPIKey(); key != "" {
+			cfg.LLM.APIKey = key
+			debugf("Using the LLM API key stored in %s", source)
+		}
+	}
+	warnRuntimeSkew(cfg.Tag, cfg.RuntimeImageOverride)
+	// Whether -port was given decides how a busy port is handled later; the
+	// probe itself lives in resolvePort, on the launch path only, so compose
+	// and -dry-run render the requested port untouched.
+	cfg.portExplicit = setFlags["port"]
+	// Re-validate: config files may have introduced a bad port or similar.
+	return ValidateFlags(cfg)
+}
+
+// CmdRun implements `openhands run TASK WORKSPACE`: it starts a session
+// that immediately works on the given task. A task argument of "-" reads
+// the task text from stdin, so long prompts can be piped in:
+//
+//	cat task.md | openhands run - ./repo
+func CmdRun(args []string) int {
+	cfg := &Config{}
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	RegisterLaunchFlags(fs, cfg)
+	var vars multiFlag
+	fs.Var(&vars, "var", "key=value for template variables in the task (repeatable)")
+	taskFile := fs.String("f", "", "read the task text from this file instead of the TASK argument")
+	fs.IntVar(&cfg.MaxIterations, "max-iterations", 0, "cap the agent's iterations before it gives up (default: server's limit)")
+	fs.DurationVar(&cfg.TaskTimeout, "timeout", 0, "stop the container when the task runs longer than this, e.g. 30m (default: no limit)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands run [flags] TASK WORKSPACE\n\nTASK may be \"-\" to read the task from stdin, or @name to use a\ntemplate from the [templates] config section. With -f FILE the TASK\nargument is omitted and the task text is read from the file.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	var task string
+	switch {
+	case *taskFile != "" && fs.NArg() == 1:
+		raw, err := os.ReadFile(*taskFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: reading task file: %v\n", err)
+			return 1
+		}
+		task = string(raw)
+		cfg.Workspace = fs.Arg(0)
+	case *taskFile == "" && fs.NArg() == 2:
+		task = fs.Arg(0)
+		cfg.Workspace = fs.Arg(1)
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	if task == "-" {
+		raw, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: reading task from stdin: %v\n", err)
+			return 1
+		}
+		task = string(raw)
+	}
+	if strings.TrimSpace(task) == "" {
+		fmt.Fprintln(os.Stderr, "openhands: the task is empty")
+		return 1
+	}
+	if err := FinalizeConfig(cfg, fs); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	// @name invokes a task template from the merged config instead of a
+	// literal task, e.g. `openhands run @review ./repo`.
+	if name, ok := strings.CutPrefix(task, "@"); ok {
+		tpl, err := LookupTemplate(cfg.Workspace, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		task = tpl.Task
+		setFlags := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+		applyTemplate(cfg, tpl, func(name string) bool { return setFlags[name] })
+	}
+	varMap, err := parseVars(vars)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 2
+	}
+	if cfg.Task, err = expandTask(task, varMap, cfg.Workspace); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if err := Run(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return ExitCode(err)
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// scanStateFile records image references that already passed a scan (or whose
+// findings the user accepted), relative to StateDir, so each tag is scanned
+// once rather than on every launch.
+const scanStateFile = "scan-ok"
+
+// scannerCmd returns the command line that scans one image and exits non-zero
+// on critical findings, using whichever supported scanner is installed.
+func scannerCmd(ref string) (*exec.Cmd, error) {
+	if _, err := exec.LookPath("trivy"); err == nil {
+		return exec.Command("trivy", "image", "--quiet", "--severity", "CRITICAL,HIGH", "--exit-code", "1", ref), nil
+	}
+	if _, err := exec.LookPath("grype"); err == nil {
+		return exec.Command("grype", "--fail-on", "critical", ref), nil
+	}
+	return nil, fmt.Errorf("image scanning requires trivy or grype on PATH; install one or drop -scan")
+}
+
+// ScanImages scans the app and runtime images before first use of a tag.
+// Findings are shown to the user; critical ones abort the launch unless
+// acceptRisk is set, in which case the acceptance is recorded and the tag is
+// not scanned again.
+func ScanImages(cfg *Config, acceptRisk bool) error {
+	for _, ref := range []string{cfg.AppImage(), cfg.RuntimeImage()} {
+		ok, err := scanRecorded(ref)
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Scanning %s for vulnerabilities...\n", ref)
+		cmd, err := scannerCmd(ref)
+		if err != nil {
+			return err
+		}
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			if _, isExit := err.(*exec.ExitError); !isExit {
+				return fmt.Errorf("running scanner: %w", err)
+			}
+			if !acceptRisk {
+				return fmt.Errorf("scan found critical vulnerabilities in %s; review the findings above and re-run with -accept-risk to proceed anyway", ref)
+			}
+			fmt.Fprintf(os.Stderr, "Proceeding despite findings in %s (-accept-risk).\n", ref)
+		}
+		if err := recordScan(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanRecorded reports whether ref already passed (or was accepted) before.
+func scanRecorded(ref string) (bool, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return false, err
+	}
+	f, err := os.Open(filepath.Join(dir, scanStateFile))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == ref {
+			return true, nil
+		}
+	}
+	return false, sc.Err()
+}
+
+// recordScan remembers that ref was scanned.
+func recordScan(ref string) error {
+	dir, err := StateDir()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, scanStateFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, ref)
+	return err
+}
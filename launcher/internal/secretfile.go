@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// llmKeyMountPath is where the API key file appears inside the app
+// container; the server reads LLM_API_KEY_FILE in preference to LLM_API_KEY.
+const llmKeyMountPath = "/run/secrets/llm_api_key"
+
+// writeLLMKeyFile stores the API key in a 0600 file under the state
+// directory so it can be mounted into the container instead of being set as
+// a plain environment variable, where `docker inspect` and process listings
+// would expose it to anyone with socket access. The file is per-process so
+// concurrent launches do not race.
+func writeLLMKeyFile(key string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	secretDir := filepath.Join(dir, "secrets")
+	if err := os.MkdirAll(secretDir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(secretDir, "llm-api-key-"+strconv.Itoa(os.Getpid()))
+	if err := os.WriteFile(path, []byte(key), 0o600); err != nil {
+		return "", fmt.Errorf("writing key file: %w", err)
+	}
+	return path, nil
+}
+
+// cleanupLLMKeyFile removes the key file written for this launch. The bind
+// mount holds the inode, so a still-running detached container keeps its
+// copy; removing the host file just stops it from lingering in ~/.openhands.
+func cleanupLLMKeyFile(cfg *Config) {
+	if cfg.llmKeyFile == "" {
+		return
+	}
+	os.Remove(cfg.llmKeyFile)
+	cfg.llmKeyFile = ""
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Secret references let config files point at an external secret manager
+// instead of containing the secret itself:
+//
+//	op://<vault>/<item>/<field>       1Password (resolved via `op read`)
+//	vault:<path>#<field>              HashiCorp Vault (`vault kv get`)
+//	aws-sm://<secret-id>[#<json-key>] AWS Secrets Manager (`aws` CLI)
+//
+// Resolution happens at launch, so the plain value never lands on disk.
+
+// isSecretRef reports whether a config value is an external reference.
+func isSecretRef(v string) bool {
+	return strings.HasPrefix(v, "op://") ||
+		strings.HasPrefix(v, "vault:") ||
+		strings.HasPrefix(v, "aws-sm://")
+}
+
+// resolveSecretRef fetches the referenced secret using the manager's own
+// CLI, so existing authentication (sessions, profiles) is reused.
+func resolveSecretRef(ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, "op://"):
+		return runSecretCmd(ref, "op", "read", "-n", ref)
+	case strings.HasPrefix(ref, "vault:"):
+		path, field, err := parseVaultRef(ref)
+		if err != nil {
+			return "", err
+		}
+		return runSecretCmd(ref, "vault", "kv", "get", "-field="+field, path)
+	case strings.HasPrefix(ref, "aws-sm://"):
+		id, key := parseAWSRef(ref)
+		out, err := runSecretCmd(ref, "aws", "secretsmanager", "get-secret-value",
+			"--secret-id", id, "--query", "SecretString", "--output", "text")
+		if err != nil || key == "" {
+			return out, err
+		}
+		var doc map[string]string
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			return "", fmt.Errorf("resolving %s: secret is not a JSON object: %w", ref, err)
+		}
+		v, ok := doc[key]
+		if !ok {
+			return "", fmt.Errorf("resolving %s: key %q not present in secret", ref, key)
+		}
+		return v, nil
+	}
+	return "", fmt.Errorf("unsupported secret reference %q", ref)
+}
+
+// parseVaultRef splits "vault:kv/openhands#key" into path and field.
+func parseVaultRef(ref string) (path, field string, err error) {
+	rest := strings.TrimPrefix(ref, "vault:")
+	path, field, ok := strings.Cut(rest, "#")
+	if !ok || path == "" || field == "" {
+		return "", "", fmt.Errorf("invalid vault reference %q (expected vault:<path>#<field>)", ref)
+	}
+	return path, field, nil
+}
+
+// parseAWSRef splits "aws-sm://my-secret#key" into secret id and optional
+// JSON key.
+func parseAWSRef(ref string) (id, key string) {
+	rest := strings.TrimPrefix(ref, "aws-sm://")
+	id, key, _ = strings.Cut(rest, "#")
+	return id, key
+}
+
+// runSecretCmd runs a secret manager CLI, with errors that name the tool so
+// a missing binary is obvious.
+func runSecretCmd(ref, name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		var detail string
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			detail = ": " + strings.TrimSpace(string(ee.Stderr))
+		}
+		return "", fmt.Errorf("resolving %s via %s failed%s: %w", ref, name, detail, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// ResolveSecretRefs replaces any secret references in the configuration with
+// their resolved values.
+func (c *Config) ResolveSecretRefs() error {
+	if isSecretRef(c.LLM.APIKey) {
+		v, err := resolveSecretRef(c.LLM.APIKey)
+		if err != nil {
+			return err
+		}
+		c.LLM.APIKey = v
+	}
+	for k, v := range c.sandboxSecrets {
+		if !isSecretRef(v) {
+			continue
+		}
+		resolved, err := resolveSecretRef(v)
+		if err != nil {
+			return err
+		}
+		c.sandboxSecrets[k] = resolved
+	}
+	return nil
+}
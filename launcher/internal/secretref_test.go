@@ -0,0 +1,42 @@
+package internal
+
+import "testing"
+
+func TestIsSecretRef(t *testing.T) {
+	cases := []struct {
+		v    string
+		want bool
+	}{
+		{"op://vault/item/field", true},
+		{"vault:kv/openhands#key", true},
+		{"aws-sm://prod/openhands", true},
+		{"sk-plain-api-key", false},
+		{"", false},
+	}
+	for _, tc := range cases {
+		if got := isSecretRef(tc.v); got != tc.want {
+			t.Errorf("isSecretRef(%q) = %v, want %v", tc.v, got, tc.want)
+		}
+	}
+}
+
+func TestParseVaultRef(t *testing.T) {
+	path, field, err := parseVaultRef("vault:kv/openhands#api_key")
+	if err != nil || path != "kv/openhands" || field != "api_key" {
+		t.Fatalf("parseVaultRef = (%q, %q, %v)", path, field, err)
+	}
+	if _, _, err := parseVaultRef("vault:kv/openhands"); err == nil {
+		t.Fatal("expected an error for a reference without a field")
+	}
+}
+
+func TestParseAWSRef(t *testing.T) {
+	id, key := parseAWSRef("aws-sm://prod/openhands#token")
+	if id != "prod/openhands" || key != "token" {
+		t.Fatalf("parseAWSRef = (%q, %q)", id, key)
+	}
+	id, key = parseAWSRef("aws-sm://plain-secret")
+	if id != "plain-secret" || key != "" {
+		t.Fatalf("parseAWSRef = (%q, %q)", id, key)
+	}
+}
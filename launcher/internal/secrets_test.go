@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	const doc = `
+# integration test credentials
+STRIPE_KEY=sk_test_123
+export GH_TOKEN="ghp_abc"
+EMPTY=
+QUOTED='with spaces'
+`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	vars, err := parseEnvFile(path)
+	if err != nil {
+		t.Fatalf("parseEnvFile: %v", err)
+	}
+	want := map[string]string{
+		"STRIPE_KEY": "sk_test_123",
+		"GH_TOKEN":   "ghp_abc",
+		"EMPTY":      "",
+		"QUOTED":     "with spaces",
+	}
+	if len(vars) != len(want) {
+		t.Fatalf("got %d vars, want %d: %v", len(vars), len(want), sortedKeys(vars))
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+}
+
+func TestParseEnvFileRejectsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	if err := os.WriteFile(path, []byte("NOT A VALID LINE\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseEnvFile(path); err == nil {
+		t.Fatal("expected an error for a malformed line")
+	}
+}
@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// releaseAPI is the GitHub endpoint queried for the latest launcher release;
+// releasesListAPI pages through past releases for changelog rendering.
+const (
+	releaseAPI      = "https://api.github.com/repos/All-Hands-AI/OpenHands/releases/latest"
+	releasesListAPI = "https://api.github.com/repos/All-Hands-AI/OpenHands/releases?per_page=20"
+)
+
+// Release is the subset of the GitHub release payload the launcher uses.
+type Release struct {
+	TagName string         `json:"tag_name"`
+	Name    string         `json:"name"`
+	Body    string         `json:"body"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset is a downloadable file attached to a release.
+type ReleaseAsset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// ImageTag returns the app image tag corresponding to this release. Published
+// images are tagged with major.minor only (e.g. "0.62" for release 0.62.1).
+func (r *Release) ImageTag() string {
+	parts := strings.Split(r.Version(), ".")
+	if len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return r.Version()
+}
+
+// LatestRelease fetches release metadata for the newest published version.
+func LatestRelease(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseAPI, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("querying GitHub releases: unexpected status %s", resp.Status)
+	}
+	var rel Release
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, fmt.Errorf("decoding release metadata: %w", err)
+	}
+	return &rel, nil
+}
+
+// ReleasesBetween fetches the releases newer than the old version, oldest
+// first, so the whole gap since the last upgrade can be shown rather than
+// only the final release's notes. The result is capped at one API page; a
+// binary more than 20 releases behind shows the most recent 20.
+func ReleasesBetween(ctx context.Context, oldVersion string) ([]*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesListAPI, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("querying GitHub releases: unexpected status %s", resp.Status)
+	}
+	var all []*Release
+	if err := json.NewDecoder(resp.Body).Decode(&all); err != nil {
+		return nil, fmt.Errorf("decoding release metadata: %w", err)
+	}
+	var newer []*Release
+	for _, rel := range all {
+		if compareVersions(rel.Version(), oldVersion) > 0 {
+			newer = append(newer, rel)
+		}
+	}
+	// The API returns newest first; the changelog reads better oldest
+	// first.
+	for i, j := 0, len(newer)-1; i < j; i, j = i+1, j-1 {
+		newer[i], newer[j] = newer[j], newer[i]
+	}
+	return newer, nil
+}
+
+// binaryAssetName is the release asset name for this platform.
+func binaryAssetName() string {
+	name := fmt.Sprintf("openhands-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// SelfUpdate downloads the launcher binary for this platform from the given
+// release and atomically replaces the running executable.
+func SelfUpdate(ctx context.Context, rel *Release) error {
+	want := binaryAssetName()
+	var url string
+	for _, a := range rel.Assets {
+		if a.Name == want {
+			url = a.DownloadURL
+			break
+		}
+	}
+	if url == "" {
+		return fmt.Errorf("release %s has no binary for %s/%s; update manually", rel.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating current executable: %w", err)
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("downloading %s: %w", want, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading %s: unexpected status %s", want, resp.Status)
+	}
+
+	// Write next to the current binary so the final rename stays on one
+	// filesystem and is atomic.
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".openhands-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		return fmt.Errorf("replacing %s: %w", exe, err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// CmdShare implements `openhands share`: it starts a viewer proxy in front
+// of a running instance and prints a tokenized URL a teammate can open to
+// watch the conversation live without being able to interact.
+//
+// Enforcement is at the HTTP layer: only safe methods pass through, and
+// websocket upgrades are refused so the UI falls back to long-polling, where
+// receiving events is a GET but sending messages or approving actions would
+// be a POST - which the proxy rejects.
+func CmdShare(args []string) int {
+	fs := flag.NewFlagSet("share", flag.ExitOnError)
+	listen := fs.String("listen", "0.0.0.0:3001", "address the observer proxy listens on")
+	target := fs.String("target", fmt.Sprintf("http://localhost:%d", DefaultPort), "base URL of the running OpenHands instance")
+	fs.Parse(args)
+
+	token, err := newObserverToken()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: generating token: %v\n", err)
+		return 1
+	}
+	targetURL, err := url.Parse(*target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: invalid target %q: %v\n", *target, err)
+		return 1
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	handler := observerHandler(proxy, token)
+
+	host := *listen
+	if strings.HasPrefix(host, "0.0.0.0") || strings.HasPrefix(host, ":") {
+		if hn, err := os.Hostname(); err == nil {
+			host = hn + host[strings.Index(host, ":"):]
+		}
+	}
+	fmt.Printf("Observer link (read-only): http://%s/?observer_token=%s\n", host, token)
+	fmt.Println("Anyone with this link can watch the conversation but not send messages. Press Ctrl-C to stop sharing.")
+
+	if err := http.ListenAndServe(*listen, handler); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+const observerCookie = "openhands_observer"
+
+// newObserverToken returns a fresh random token.
+func newObserverToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// observerHandler wraps the proxy with token and read-only enforcement. The
+// token is accepted once as a query parameter and then carried in a cookie,
+// so asset requests from the UI keep working.
+func observerHandler(proxy http.Handler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !observerAuthorized(r, token) {
+			http.Error(w, "missing or invalid observer token", http.StatusForbidden)
+			return
+		}
+		if q := r.URL.Query().Get("observer_token"); q != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     observerCookie,
+				Value:    q,
+				Path:     "/",
+				HttpOnly: true,
+			})
+		}
+		if !observerMethodAllowed(r) {
+			http.Error(w, "this is a read-only observer link", http.StatusForbidden)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+func observerAuthorized(r *http.Request, token string) bool {
+	candidate := r.URL.Query().Get("observer_token")
+	if candidate == "" {
+		if c, err := r.Cookie(observerCookie); err == nil {
+			candidate = c.Value
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1
+}
+
+// observerMethodAllowed restricts observers to reading. Websocket upgrades
+// are refused because frames inside an established socket cannot be
+// filtered; the UI then falls back to polling, where sends are POSTs.
+func observerMethodAllowed(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+	return !strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
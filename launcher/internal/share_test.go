@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestObserverHandler(t *testing.T) {
+	backend := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := observerHandler(backend, "tok123")
+
+	get := func(target string, setup func(*http.Request)) int {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if setup != nil {
+			setup(r)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := get("/", nil); code != http.StatusForbidden {
+		t.Errorf("no token: code = %d, want 403", code)
+	}
+	if code := get("/?observer_token=wrong", nil); code != http.StatusForbidden {
+		t.Errorf("bad token: code = %d, want 403", code)
+	}
+	if code := get("/?observer_token=tok123", nil); code != http.StatusOK {
+		t.Errorf("good token: code = %d, want 200", code)
+	}
+	if code := get("/", func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: observerCookie, Value: "tok123"})
+	}); code != http.StatusOK {
+		t.Errorf("cookie token: code = %d, want 200", code)
+	}
+
+	// Writes and websocket upgrades must be refused even with the token.
+	r := httptest.NewRequest(http.MethodPost, "/?observer_token=tok123", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("POST: code = %d, want 403", w.Code)
+	}
+	if code := get("/socket.io/?observer_token=tok123", func(r *http.Request) {
+		r.Header.Set("Upgrade", "websocket")
+	}); code != http.StatusForbidden {
+		t.Errorf("websocket upgrade: code = %d, want 403", code)
+	}
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// CmdShell implements `openhands shell [NAME]` (also reachable as `attach`):
+// an interactive shell inside the app container, replacing the usual
+// `docker ps` + `docker exec -it ... bash` dance. -runtime targets the
+// sandbox runtime container instead, where the agent's commands actually
+// run. The shell's exit status becomes the command's.
+func CmdShell(args []string) int {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	runtimeTarget := fs.Bool("runtime", false, "exec into the sandbox runtime container instead of the app's")
+	contextName := fs.String("context", "", "docker CLI context the instance runs on")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands shell [flags] [NAME]\n\nNAME defaults to the single running instance.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := applyContextFlag(*contextName); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	id, err := resolveLogTarget(ctx, cli, fs.Arg(0), *runtimeTarget)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	code, err := execShell(ctx, cli, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	return code
+}
+
+// execShell runs an interactive shell in the container and returns its exit
+// status. bash is preferred; slim images without it fall back to sh.
+func execShell(ctx context.Context, cli *client.Client, id string) (int, error) {
+	shell := "bash"
+	if !execSucceeds(ctx, cli, id, []string{"bash", "-c", "true"}) {
+		shell = "sh"
+	}
+	interactive := interactiveSession()
+	resp, err := cli.ContainerExecCreate(ctx, id, types.ExecConfig{
+		Cmd:          []string{shell},
+		Tty:          interactive,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("creating %s session: %w", shell, err)
+	}
+	attach, err := cli.ContainerExecAttach(ctx, resp.ID, types.ExecStartCheck{Tty: interactive})
+	if err != nil {
+		return 0, fmt.Errorf("attaching to %s session: %w", shell, err)
+	}
+	defer attach.Close()
+	if interactive {
+		cleanup := setupSession(ctx, attach, func() { execResize(ctx, cli, resp.ID) })
+		defer cleanup()
+		// A TTY session is a single raw stream.
+		io.Copy(os.Stdout, attach.Reader)
+	} else {
+		go func() {
+			io.Copy(attach.Conn, os.Stdin)
+			attach.CloseWrite()
+		}()
+		// Without a TTY the stream is multiplexed stdout/stderr.
+		stdcopy.StdCopy(os.Stdout, os.Stderr, attach.Reader)
+	}
+	insp, err := cli.ContainerExecInspect(ctx, resp.ID)
+	if err != nil {
+		return 0, fmt.Errorf("inspecting %s session: %w", shell, err)
+	}
+	return insp.ExitCode, nil
+}
+
+// execSucceeds runs a short non-interactive command in the container and
+// reports whether it exited zero; used to probe for an available shell.
+func execSucceeds(ctx context.Context, cli *client.Client, id string, cmd []string) bool {
+	resp, err := cli.ContainerExecCreate(ctx, id, types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return false
+	}
+	attach, err := cli.ContainerExecAttach(ctx, resp.ID, types.ExecStartCheck{})
+	if err != nil {
+		return false
+	}
+	io.Copy(io.Discard, attach.Reader)
+	attach.Close()
+	insp, err := cli.ContainerExecInspect(ctx, resp.ID)
+	return err == nil && insp.ExitCode == 0
+}
+
+// execResize pushes the current terminal size to an exec session's PTY.
+func execResize(ctx context.Context, cli *client.Client, execID string) {
+	w, h := terminalSize(os.Stdout.Fd())
+	cli.ContainerExecResize(ctx, execID, types.ResizeOptions{Width: uint(w), Height: uint(h)})
+}
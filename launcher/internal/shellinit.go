@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Subcommands returns the names of all registered subcommands, sorted. It is
+// set from main so the shell integration can offer completion without the
+// two packages importing each other.
+var Subcommands = func() []string { return nil }
+
+// CmdShellInit implements `openhands shell-init [bash|zsh|fish]`. It prints a
+// snippet to be eval'd from the user's shell rc file:
+//
+//	eval "$(openhands shell-init bash)"
+//
+// The snippet wires up completion and a cheap cd hook that points out when
+// the current repository carries OpenHands configuration.
+func CmdShellInit(args []string) int {
+	fs := flag.NewFlagSet("shell-init", flag.ExitOnError)
+	fs.Parse(args)
+
+	shell := fs.Arg(0)
+	if shell == "" {
+		shell = filepath.Base(os.Getenv("SHELL"))
+	}
+	names := make([]string, 0, len(Subcommands()))
+	for _, name := range Subcommands() {
+		// Internal helpers like __complete are not offered to users.
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	cmds := strings.Join(names, " ")
+
+	switch shell {
+	case "bash":
+		fmt.Printf(bashInit, cmds)
+	case "zsh":
+		fmt.Printf(zshInit, cmds)
+	case "fish":
+		fmt.Printf(fishInit, cmds)
+	default:
+		fmt.Fprintf(os.Stderr, "openhands: unsupported shell %q (expected bash, zsh or fish)\n", shell)
+		return 1
+	}
+	return 0
+}
+
+// The hooks keep per-shell memory of directories already hinted about so the
+// message appears once per repo, not on every prompt.
+
+const bashInit = `# OpenHands shell integration (bash)
+_openhands_complete() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local prev="${COMP_WORDS[COMP_CWORD-1]}"
+    if [ "$COMP_CWORD" -eq 1 ]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -W "$(openhands __complete "$prev" "$cur" 2>/dev/null)" -- "$cur"))
+    fi
+    COMPREPLY+=($(compgen -d -- "$cur"))
+}
+complete -F _openhands_complete openhands
+
+_openhands_cd_hint() {
+    if [ "$PWD" != "${_OPENHANDS_HINTED:-}" ] && [ -d "$PWD/.openhands" ]; then
+        _OPENHANDS_HINTED="$PWD"
+        echo "This repository has OpenHands configuration; start the agent here with: openhands ." >&2
+    fi
+}
+case "$PROMPT_COMMAND" in
+    *_openhands_cd_hint*) ;;
+    *) PROMPT_COMMAND="_openhands_cd_hint${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`
+
+const zshInit = `# OpenHands shell integration (zsh)
+_openhands_complete() {
+    local -a cmds
+    cmds=(%s)
+    if (( CURRENT == 2 )); then
+        compadd -a cmds
+    else
+        compadd -- $(openhands __complete "${words[CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)
+    fi
+    _files -/
+}
+compdef _openhands_complete openhands 2>/dev/null
+
+_openhands_cd_hint() {
+    if [ "$PWD" != "${_OPENHANDS_HINTED:-}" ] && [ -d "$PWD/.openhands" ]; then
+        _OPENHANDS_HINTED="$PWD"
+        echo "This repository has OpenHands configuration; start the agent here with: openhands ." >&2
+    fi
+}
+autoload -Uz add-zsh-hook 2>/dev/null && add-zsh-hook chpwd _openhands_cd_hint
+`
+
+const fishInit = `# OpenHands shell integration (fish)
+complete -c openhands -n "__fish_use_subcommand" -a "%s"
+complete -c openhands -a "(openhands __complete (commandline -opc)[-1] (commandline -ct) 2>/dev/null)"
+complete -c openhands -a "(__fish_complete_directories)"
+
+function _openhands_cd_hint --on-variable PWD
+    if test -d "$PWD/.openhands"; and test "$PWD" != "$_openhands_hinted"
+        set -g _openhands_hinted $PWD
+        echo "This repository has OpenHands configuration; start the agent here with: openhands ." >&2
+    end
+end
+`
@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// CmdStatus implements `openhands status`: a numeric summary of the current
+// conversation - iterations, tool calls by kind, errors, and time spent in
+// each agent state - so a stuck loop shows up as a number ("412 runs, 0
+// edits, 2h in running") instead of something to be read out of the logs.
+func CmdStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	conv := fs.String("conversation", "", "conversation ID (defaults to the most recent)")
+	asJSON := fs.Bool("json", false, "emit the summary as JSON")
+	fs.Parse(args)
+
+	client := newEventsClient(fmt.Sprintf("http://localhost:%d", *port))
+	convID := *conv
+	if convID == "" {
+		var err error
+		if convID, err = client.LatestConversation(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	events, err := client.Events(convID, -1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	metrics := computeMetrics(events, time.Now())
+	if *asJSON {
+		if err := printJSON(statusJSONFrom(convID, metrics)); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	printMetrics(os.Stdout, convID, metrics)
+	return 0
+}
+
+// conversationMetrics is what computeMetrics distills out of an event stream.
+type conversationMetrics struct {
+	Events      int
+	Iterations  int // agent actions, the server's iteration unit
+	ToolCalls   map[string]int
+	Errors      int
+	StateTime   map[string]time.Duration
+	LastState   string
+	UserMsgs    int
+	FirstActive time.Time
+}
+
+// computeMetrics tallies a conversation's event stream. State durations are
+// derived from the agent_state_changed observations; the final state's time
+// runs up to now.
+func computeMetrics(events []Event, now time.Time) conversationMetrics {
+	m := conversationMetrics{
+		ToolCalls: make(map[string]int),
+		StateTime: make(map[string]time.Duration),
+	}
+	var stateSince time.Time
+	for i := range events {
+		ev := &events[i]
+		m.Events++
+		ts := eventTime(ev)
+		if m.FirstActive.IsZero() && !ts.IsZero() {
+			m.FirstActive = ts
+		}
+		switch {
+		case ev.Source == "agent" && ev.Action != "":
+			m.Iterations++
+			if ev.Action != "message" && ev.Action != "change_agent_state" {
+				m.ToolCalls[ev.Action]++
+			}
+		case ev.Source == "user" && ev.Action == "message":
+			m.UserMsgs++
+		}
+		if ev.Observation == "error" {
+			m.Errors++
+		}
+		if ev.Observation == "agent_state_changed" {
+			state, _ := ev.Raw["extras"].(map[string]interface{})
+			name, _ := state["agent_state"].(string)
+			if name == "" {
+				continue
+			}
+			if m.LastState != "" && !stateSince.IsZero() && !ts.IsZero() {
+				m.StateTime[m.LastState] += ts.Sub(stateSince)
+			}
+			m.LastState = name
+			stateSince = ts
+		}
+	}
+	if m.LastState != "" && !stateSince.IsZero() && now.After(stateSince) {
+		m.StateTime[m.LastState] += now.Sub(stateSince)
+	}
+	return m
+}
+
+// eventTime parses the event timestamp, returning the zero time when the
+// format is unknown.
+func eventTime(ev *Event) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999"} {
+		if ts, err := time.Parse(layout, ev.Timestamp); err == nil {
+			return ts
+		}
+	}
+	return time.Time{}
+}
+
+// printMetrics renders the summary.
+func printMetrics(w *os.File, convID string, m conversationMetrics) {
+	fmt.Fprintf(w, "Conversation %s\n", convID)
+	fmt.Fprintf(w, "  events:      %d\n", m.Events)
+	fmt.Fprintf(w, "  iterations:  %d\n", m.Iterations)
+	fmt.Fprintf(w, "  user msgs:   %d\n", m.UserMsgs)
+	fmt.Fprintf(w, "  errors:      %d\n", m.Errors)
+	if len(m.ToolCalls) > 0 {
+		fmt.Fprintf(w, "  tool calls:\n")
+		kinds := make([]string, 0, len(m.ToolCalls))
+		for k := range m.ToolCalls {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		for _, k := range kinds {
+			fmt.Fprintf(w, "    %-12s %d\n", k, m.ToolCalls[k])
+		}
+	}
+	if len(m.StateTime) > 0 {
+		fmt.Fprintf(w, "  time in state:\n")
+		states := make([]string, 0, len(m.StateTime))
+		for s := range m.StateTime {
+			states = append(states, s)
+		}
+		sort.Strings(states)
+		for _, s := range states {
+			fmt.Fprintf(w, "    %-12s %s\n", s, m.StateTime[s].Round(time.Second))
+		}
+	}
+	if m.LastState != "" {
+		fmt.Fprintf(w, "  agent state: %s\n", m.LastState)
+	}
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func stateEvent(ts, state string) Event {
+	return Event{
+		Timestamp:   ts,
+		Source:      "environment",
+		Observation: "agent_state_changed",
+		Raw: map[string]interface{}{
+			"extras": map[string]interface{}{"agent_state": state},
+		},
+	}
+}
+
+func TestComputeMetrics(t *testing.T) {
+	events := []Event{
+		{Source: "user", Action: "message", Timestamp: "2026-08-28T10:00:00Z"},
+		stateEvent("2026-08-28T10:00:01Z", "running"),
+		{Source: "agent", Action: "run", Timestamp: "2026-08-28T10:00:05Z"},
+		{Source: "agent", Action: "edit", Timestamp: "2026-08-28T10:00:10Z"},
+		{Source: "agent", Action: "run", Timestamp: "2026-08-28T10:00:15Z"},
+		{Source: "environment", Observation: "error", Timestamp: "2026-08-28T10:00:16Z"},
+		stateEvent("2026-08-28T10:01:01Z", "awaiting_user_input"),
+	}
+	now := time.Date(2026, 8, 28, 10, 2, 1, 0, time.UTC)
+	m := computeMetrics(events, now)
+
+	if m.Iterations != 3 {
+		t.Errorf("Iterations = %d, want 3", m.Iterations)
+	}
+	if m.ToolCalls["run"] != 2 || m.ToolCalls["edit"] != 1 {
+		t.Errorf("ToolCalls = %v", m.ToolCalls)
+	}
+	if m.Errors != 1 || m.UserMsgs != 1 {
+		t.Errorf("Errors = %d, UserMsgs = %d", m.Errors, m.UserMsgs)
+	}
+	if got := m.StateTime["running"]; got != time.Minute {
+		t.Errorf("time in running = %s, want 1m", got)
+	}
+	if got := m.StateTime["awaiting_user_input"]; got != time.Minute {
+		t.Errorf("time in awaiting_user_input = %s, want 1m", got)
+	}
+	if m.LastState != "awaiting_user_input" {
+		t.Errorf("LastState = %q", m.LastState)
+	}
+}
+
+func TestComputeMetricsEmpty(t *testing.T) {
+	m := computeMetrics(nil, time.Now())
+	if m.Events != 0 || m.Iterations != 0 || len(m.StateTime) != 0 {
+		t.Errorf("metrics of empty stream not zero: %+v", m)
+	}
+}
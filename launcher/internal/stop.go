@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// CmdStop implements `openhands stop [NAME]`: it stops app containers the
+// launcher started (matched by label, so hand-started containers are left
+// alone). With exactly one instance running no argument is needed; with
+// several, the candidates are listed and a name or -all is required.
+func CmdStop(args []string) int {
+	fs := flag.NewFlagSet("stop", flag.ExitOnError)
+	all := fs.Bool("all", false, "stop every running instance")
+	timeout := fs.Duration("timeout", 10*time.Second, "grace period before the container is killed")
+	contextName := fs.String("context", "", "docker CLI context the instance runs on")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands stop [flags] [NAME]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := applyContextFlag(*contextName); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	instances, err := listInstances(ctx, cli)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if len(instances) == 0 {
+		fmt.Fprintln(os.Stderr, "openhands: no running instances")
+		return 1
+	}
+
+	var targets []types.Container
+	switch {
+	case *all:
+		targets = instances
+	case fs.NArg() == 1:
+		name := fs.Arg(0)
+		for _, c := range instances {
+			if containerName(c) == name || strings.HasPrefix(c.ID, name) {
+				targets = append(targets, c)
+			}
+		}
+		if len(targets) == 0 {
+			fmt.Fprintf(os.Stderr, "openhands: no running instance named %q\n", name)
+			return 1
+		}
+	case len(instances) == 1:
+		targets = instances
+	default:
+		fmt.Fprintln(os.Stderr, "openhands: several instances are running; name one or pass -all:")
+		for _, c := range instances {
+			fmt.Fprintf(os.Stderr, "  %s  (workspace %s)\n", containerName(c), c.Labels[LabelWorkspace])
+		}
+		return 1
+	}
+
+	seconds := int(timeout.Seconds())
+	failed := false
+	for _, c := range targets {
+		if err := cli.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &seconds}); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: stopping %s: %v\n", containerName(c), err)
+			failed = true
+			continue
+		}
+		unregisterInstance(containerName(c))
+		fmt.Printf("Stopped %s\n", containerName(c))
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// listInstances returns the running app containers the launcher manages.
+// Warm pool members are excluded; `openhands pool` owns their lifecycle.
+// Listing also prunes the launch registry, so ps/stop/logs heal any entries
+// left behind by a killed launcher process.
+func listInstances(ctx context.Context, cli *client.Client) ([]types.Container, error) {
+	pruneRegistry(ctx, cli)
+	f := filters.NewArgs(filters.Arg("label", LabelManaged+"=true"))
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{Filters: f})
+	if err != nil {
+		return nil, err
+	}
+	var out []types.Container
+	for _, c := range containers {
+		if _, pooled := c.Labels[LabelPool]; pooled {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// containerName returns the container's primary name without the leading
+// slash the API adds.
+func containerName(c types.Container) string {
+	if len(c.Names) == 0 {
+		return c.ID[:12]
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Task strings may contain template variables filled from repeated -var
+// flags plus a few built-ins derived from the workspace:
+//
+//	openhands run -var package=lodash -var version=5 \
+//	    "bump {{package}} to {{version}}" ./repo
+//
+// Both {{name}} and the canonical Go-template form {{.name}} are accepted.
+
+// bareVarRe rewrites the convenient {{name}} spelling into a map lookup.
+var bareVarRe = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// varRefRe finds all variable references, for undefined-variable checking.
+var varRefRe = regexp.MustCompile(`\{\{\s*\.?([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// parseVars turns repeated "key=value" flags into a map.
+func parseVars(vars []string) (map[string]string, error) {
+	out := make(map[string]string, len(vars))
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid -var %q (expected key=value)", v)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// expandTask renders the task template. Built-in variables are only added
+// when the user has not defined them, so -var workspace=... still wins.
+func expandTask(task string, vars map[string]string, workspace string) (string, error) {
+	data := make(map[string]string, len(vars)+2)
+	for k, v := range vars {
+		data[k] = v
+	}
+	if _, ok := data["workspace"]; !ok {
+		data["workspace"] = filepath.Base(workspace)
+	}
+	if _, ok := data["branch"]; !ok {
+		data["branch"] = gitBranch(workspace)
+	}
+
+	for _, m := range varRefRe.FindAllStringSubmatch(task, -1) {
+		if _, ok := data[m[1]]; !ok {
+			return "", fmt.Errorf("task references undefined variable {{%s}} (define it with -var %s=...)", m[1], m[1])
+		}
+	}
+	normalized := bareVarRe.ReplaceAllString(task, `{{index . "$1"}}`)
+	tmpl, err := template.New("task").Parse(normalized)
+	if err != nil {
+		return "", fmt.Errorf("invalid task template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("expanding task template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// gitBranch returns the workspace's current branch, or "" outside a git
+// checkout.
+func gitBranch(workspace string) string {
+	cmd := exec.Command("git", "-C", workspace, "rev-parse", "--abbrev-ref", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandTask(t *testing.T) {
+	vars := map[string]string{"package": "lodash", "version": "5"}
+	got, err := expandTask("bump {{package}} to {{ version }}", vars, "/home/me/proj")
+	if err != nil {
+		t.Fatalf("expandTask: %v", err)
+	}
+	if got != "bump lodash to 5" {
+		t.Fatalf("expandTask = %q", got)
+	}
+}
+
+func TestExpandTaskBuiltinsAndDotForm(t *testing.T) {
+	got, err := expandTask("work in {{.workspace}}", nil, "/home/me/proj")
+	if err != nil {
+		t.Fatalf("expandTask: %v", err)
+	}
+	if got != "work in proj" {
+		t.Fatalf("expandTask = %q", got)
+	}
+	// User-provided values beat built-ins.
+	got, err = expandTask("{{workspace}}", map[string]string{"workspace": "custom"}, "/home/me/proj")
+	if err != nil || got != "custom" {
+		t.Fatalf("expandTask = (%q, %v)", got, err)
+	}
+}
+
+func TestExpandTaskUndefinedVariable(t *testing.T) {
+	_, err := expandTask("bump {{package}}", nil, "/w")
+	if err == nil || !strings.Contains(err.Error(), "package") {
+		t.Fatalf("expected undefined-variable error naming the variable, got %v", err)
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := parseVars([]string{"a=1", "b=x=y"})
+	if err != nil || vars["a"] != "1" || vars["b"] != "x=y" {
+		t.Fatalf("parseVars = (%v, %v)", vars, err)
+	}
+	if _, err := parseVars([]string{"novalue"}); err == nil {
+		t.Fatal("expected an error for a var without '='")
+	}
+}
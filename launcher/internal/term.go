@@ -0,0 +1,18 @@
+package internal
+
+import "golang.org/x/term"
+
+// isTerminal reports whether the given file descriptor is attached to a
+// terminal.
+func isTerminal(fd uintptr) bool {
+	return term.IsTerminal(int(fd))
+}
+
+// terminalSize returns the terminal dimensions for fd, falling back to a
+// conventional 80x24 when fd is not a terminal.
+func terminalSize(fd uintptr) (width, height int) {
+	if w, h, err := term.GetSize(int(fd)); err == nil {
+		return w, h
+	}
+	return 80, 24
+}
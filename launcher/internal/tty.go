@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"golang.org/x/term"
+)
+
+// restoreTTY undoes the raw-mode switch made by setupInteractive. It is a
+// package variable so the signal handler in Run can restore the terminal
+// before printing shutdown messages; sync.Once makes the restore safe to
+// trigger from both the signal path and the normal exit path.
+var (
+	restoreTTY  func()
+	restoreOnce sync.Once
+)
+
+// restoreTerminalState puts the terminal back into its original mode. Safe
+// to call when no interactive session was set up.
+func restoreTerminalState() {
+	restoreOnce.Do(func() {
+		if restoreTTY != nil {
+			restoreTTY()
+		}
+	})
+}
+
+// interactiveSession reports whether the launch should wire the local
+// terminal through to the container: both ends must be terminals, otherwise
+// (pipes, CI) the plain output-streaming branch is used.
+func interactiveSession() bool {
+	return isTerminal(os.Stdin.Fd()) && isTerminal(os.Stdout.Fd())
+}
+
+// setupInteractive makes the attached container actually usable from a
+// terminal: the local terminal goes into raw mode (so keystrokes, including
+// control sequences, reach the container instead of the local line
+// discipline), stdin is copied into the attach stream, and terminal resizes
+// are propagated so the container's PTY tracks the real window instead of
+// staying at 80x24. The returned cleanup restores the terminal mode and must
+// run before anything else writes to the terminal after the session ends.
+func setupInteractive(ctx context.Context, cli *client.Client, id string, attach types.HijackedResponse) func() {
+	return setupSession(ctx, attach, func() { resizeContainer(ctx, cli, id) })
+}
+
+// setupSession is the raw-mode, stdin and resize wiring shared by container
+// attaches and exec sessions; resize pushes the terminal size to whichever
+// PTY the session runs on.
+func setupSession(ctx context.Context, attach types.HijackedResponse, resize func()) func() {
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return func() {}
+	}
+	restoreTTY = func() { term.Restore(int(os.Stdin.Fd()), oldState) }
+
+	go func() {
+		io.Copy(attach.Conn, os.Stdin)
+		attach.CloseWrite()
+	}()
+
+	resize()
+	go watchResize(ctx, resize)
+	return restoreTerminalState
+}
+
+// resizeContainer pushes the current terminal size to the container's PTY.
+// Failures are ignored: the container may not be running yet, and a stale
+// size is harmless.
+func resizeContainer(ctx context.Context, cli *client.Client, id string) {
+	w, h := terminalSize(os.Stdout.Fd())
+	cli.ContainerResize(ctx, id, types.ResizeOptions{Width: uint(w), Height: uint(h)})
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package internal
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize re-pushes the terminal size to the session's PTY whenever the
+// window changes, driven by SIGWINCH.
+func watchResize(ctx context.Context, resize func()) {
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-winch:
+			resize()
+		}
+	}
+}
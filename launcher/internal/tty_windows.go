@@ -0,0 +1,28 @@
+//go:build windows
+
+package internal
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// watchResize re-pushes the terminal size to the session's PTY when the
+// window changes. Windows has no SIGWINCH, so the size is polled instead.
+func watchResize(ctx context.Context, resize func()) {
+	lastW, lastH := terminalSize(os.Stdout.Fd())
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w, h := terminalSize(os.Stdout.Fd()); w != lastW || h != lastH {
+				lastW, lastH = w, h
+				resize()
+			}
+		}
+	}
+}
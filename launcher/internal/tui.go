@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// CmdView implements `openhands view`, a browser-free split-pane terminal
+// client for a running instance: container logs in the top pane, parsed
+// agent events in the middle, and an input line for sending user messages at
+// the bottom. Ctrl-C leaves the view without touching the instance.
+func CmdView(args []string) int {
+	fs := flag.NewFlagSet("view", flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	fs.Parse(args)
+
+	if !isTerminal(os.Stdout.Fd()) || !isTerminal(os.Stdin.Fd()) {
+		fmt.Fprintln(os.Stderr, "openhands: view needs an interactive terminal")
+		return 1
+	}
+
+	v := &splitView{
+		client: newEventsClient(fmt.Sprintf("http://localhost:%d", *port)),
+	}
+	if err := v.run(); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+const paneBuffer = 500
+
+type splitView struct {
+	client *eventsClient
+
+	mu     sync.Mutex
+	logs   []string
+	events []string
+	input  []rune
+	status string
+	done   bool
+}
+
+func (v *splitView) run() error {
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	defer term.Restore(int(os.Stdin.Fd()), oldState)
+	defer fmt.Print("\x1b[2J\x1b[H\x1b[?25h") // clear and restore cursor
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go v.followLogs(ctx)
+	go v.followEvents(ctx)
+	go func() {
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				v.render()
+			}
+		}
+	}()
+
+	v.setStatus("Connected. Type a message and press Enter to send; Ctrl-C exits the view.")
+	return v.readInput()
+}
+
+// followLogs streams the app container's output into the logs pane.
+func (v *splitView) followLogs(ctx context.Context) {
+	cli, err := NewDockerClient()
+	if err != nil {
+		v.appendLog("(logs unavailable: " + err.Error() + ")")
+		return
+	}
+	defer cli.Close()
+	rc, err := ContainerLogsFollow(ctx, cli, ContainerName, "100")
+	if err != nil {
+		v.appendLog("(logs unavailable: " + err.Error() + ")")
+		return
+	}
+	defer rc.Close()
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		v.appendLog(scanner.Text())
+	}
+}
+
+// followEvents polls the conversation event feed into the events pane.
+func (v *splitView) followEvents(ctx context.Context) {
+	var convID string
+	lastID := -1
+	for ctx.Err() == nil {
+		if convID == "" {
+			id, err := v.client.LatestConversation()
+			if err != nil {
+				v.setStatus("Waiting for a conversation to start...")
+				time.Sleep(2 * time.Second)
+				continue
+			}
+			convID = id
+			v.setStatus("Following conversation " + convID)
+		}
+		events, err := v.client.Events(convID, lastID)
+		if err == nil {
+			v.mu.Lock()
+			for i := range events {
+				v.events = appendBounded(v.events, events[i].Summary())
+				if events[i].ID > lastID {
+					lastID = events[i].ID
+				}
+			}
+			v.mu.Unlock()
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// readInput handles keystrokes on the raw terminal.
+func (v *splitView) readInput() error {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return err
+		}
+		switch r {
+		case 0x03: // Ctrl-C
+			return nil
+		case '\r', '\n':
+			v.mu.Lock()
+			text := strings.TrimSpace(string(v.input))
+			v.input = v.input[:0]
+			v.mu.Unlock()
+			if text == "" {
+				continue
+			}
+			go v.send(text)
+		case 0x7f, '\b':
+			v.mu.Lock()
+			if len(v.input) > 0 {
+				v.input = v.input[:len(v.input)-1]
+			}
+			v.mu.Unlock()
+		default:
+			if r >= ' ' {
+				v.mu.Lock()
+				v.input = append(v.input, r)
+				v.mu.Unlock()
+			}
+		}
+		v.render()
+	}
+}
+
+func (v *splitView) send(text string) {
+	convID, err := v.client.LatestConversation()
+	if err != nil {
+		v.setStatus("Cannot send: " + err.Error())
+		return
+	}
+	if err := v.client.SendMessage(convID, text); err != nil {
+		v.setStatus("Cannot send: " + err.Error())
+		return
+	}
+	v.setStatus("Message sent.")
+}
+
+func (v *splitView) appendLog(line string) {
+	v.mu.Lock()
+	v.logs = appendBounded(v.logs, line)
+	v.mu.Unlock()
+}
+
+func (v *splitView) setStatus(s string) {
+	v.mu.Lock()
+	v.status = s
+	v.mu.Unlock()
+}
+
+func appendBounded(buf []string, line string) []string {
+	buf = append(buf, line)
+	if len(buf) > paneBuffer {
+		buf = buf[len(buf)-paneBuffer:]
+	}
+	return buf
+}
+
+// render redraws the whole screen. Full redraws at a few Hz are cheap enough
+// and keep the drawing logic trivial; the terminal size is re-read each time
+// so resizes are picked up without signal handling.
+func (v *splitView) render() {
+	width, height := terminalSize(os.Stdout.Fd())
+	if height < 8 {
+		return
+	}
+	logRows := (height - 3) / 2
+	eventRows := height - 3 - logRows
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var b strings.Builder
+	b.WriteString("\x1b[?25l\x1b[H") // hide cursor, home
+	writePane(&b, "container logs", v.logs, logRows, width)
+	writePane(&b, "agent events", v.events, eventRows, width)
+	fmt.Fprintf(&b, "\x1b[K%s\r\n", clip(v.status, width))
+	fmt.Fprintf(&b, "\x1b[K> %s\x1b[?25h", clip(string(v.input), width-2))
+	os.Stdout.WriteString(b.String())
+}
+
+func writePane(b *strings.Builder, title string, lines []string, rows, width int) {
+	fmt.Fprintf(b, "\x1b[K\x1b[7m %s \x1b[0m\r\n", clip(title, width-2))
+	rows--
+	start := 0
+	if len(lines) > rows {
+		start = len(lines) - rows
+	}
+	for i := start; i < len(lines); i++ {
+		fmt.Fprintf(b, "\x1b[K%s\r\n", clip(lines[i], width))
+	}
+	for i := len(lines) - start; i < rows; i++ {
+		b.WriteString("\x1b[K\r\n")
+	}
+}
+
+// clip truncates a line to the terminal width, stripping carriage returns
+// that would corrupt the layout.
+func clip(s string, width int) string {
+	s = strings.ReplaceAll(s, "\r", "")
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return s
+}
@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+)
+
+// CmdUninstall implements `openhands uninstall`: it removes launcher-managed
+// containers and volumes, the OpenHands images, and the state directory, so
+// an installation can be wiped or a broken one reset.
+func CmdUninstall(args []string) int {
+	fs := flag.NewFlagSet("uninstall", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "skip the confirmation prompt")
+	resetState := fs.Bool("reset-state", false, "only remove containers and the state directory, keeping images and the binary")
+	fs.Parse(args)
+
+	what := "all OpenHands containers, volumes, images and ~/.openhands"
+	if *resetState {
+		what = "all OpenHands containers and ~/.openhands (keeping images)"
+	}
+	if !*yes && !confirm(fmt.Sprintf("This will remove %s. Proceed?", what)) {
+		fmt.Println("Aborted.")
+		return 1
+	}
+
+	ctx := context.Background()
+	cli, err := NewDockerClient()
+	if err == nil {
+		err = PingDaemon(ctx, cli)
+	}
+	if err != nil {
+		// Docker being gone should not block wiping local state.
+		fmt.Fprintf(os.Stderr, "openhands: skipping container cleanup: %v\n", err)
+		cli = nil
+	}
+
+	failed := false
+	if cli != nil {
+		defer cli.Close()
+		if err := removeContainers(ctx, cli); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			failed = true
+		}
+		if err := removeVolumes(ctx, cli); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			failed = true
+		}
+		if !*resetState {
+			if err := removeImages(ctx, cli); err != nil {
+				fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+				failed = true
+			}
+		}
+	}
+	if err := removeStateDir(); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		failed = true
+	}
+	if !*resetState {
+		if err := removeServiceUnits(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	fmt.Println("OpenHands has been removed.")
+	return 0
+}
+
+// confirm asks a yes/no question on the terminal and defaults to no.
+func confirm(prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// managedFilter matches everything the launcher has created.
+func managedFilter() filters.Args {
+	return filters.NewArgs(filters.Arg("label", LabelManaged+"=true"))
+}
+
+func removeContainers(ctx context.Context, cli *client.Client) error {
+	list, err := cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: managedFilter(),
+	})
+	if err != nil {
+		return fmt.Errorf("listing containers: %w", err)
+	}
+	for _, c := range list {
+		fmt.Printf("Removing container %s\n", strings.TrimPrefix(c.Names[0], "/"))
+		if err := cli.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return fmt.Errorf("removing container %s: %w", c.ID[:12], err)
+		}
+	}
+	return nil
+}
+
+func removeVolumes(ctx context.Context, cli *client.Client) error {
+	list, err := cli.VolumeList(ctx, volume.ListOptions{Filters: managedFilter()})
+	if err != nil {
+		return fmt.Errorf("listing volumes: %w", err)
+	}
+	for _, v := range list.Volumes {
+		fmt.Printf("Removing volume %s\n", v.Name)
+		if err := cli.VolumeRemove(ctx, v.Name, true); err != nil {
+			return fmt.Errorf("removing volume %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+func removeImages(ctx context.Context, cli *client.Client) error {
+	list, err := cli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing images: %w", err)
+	}
+	for _, img := range list {
+		for _, tag := range img.RepoTags {
+			if !strings.HasPrefix(tag, DefaultAppImage+":") && !strings.HasPrefix(tag, DefaultRuntimeImage+":") {
+				continue
+			}
+			fmt.Printf("Removing image %s\n", tag)
+			if _, err := cli.ImageRemove(ctx, tag, types.ImageRemoveOptions{Force: true, PruneChildren: true}); err != nil {
+				return fmt.Errorf("removing image %s: %w", tag, err)
+			}
+		}
+	}
+	return nil
+}
+
+func removeStateDir() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(home, ".openhands")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	fmt.Printf("Removing %s\n", dir)
+	return os.RemoveAll(dir)
+}
+
+// removeServiceUnits deletes any systemd units a user may have installed to
+// run OpenHands in the background.
+func removeServiceUnits() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	for _, unit := range []string{
+		filepath.Join(home, ".config", "systemd", "user", "openhands.service"),
+		"/etc/systemd/system/openhands.service",
+	} {
+		if _, err := os.Stat(unit); os.IsNotExist(err) {
+			continue
+		}
+		fmt.Printf("Removing service unit %s\n", unit)
+		if err := os.Remove(unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// updateCheckCacheTTL bounds how often the launcher asks GitHub about new
+// releases; within the window the cached answer is reused.
+const updateCheckCacheTTL = 24 * time.Hour
+
+// updateCheckCache is the on-disk record of the last release lookup.
+type updateCheckCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest"`
+}
+
+func updateCachePath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "update-check.json"), nil
+}
+
+// latestKnownVersion returns the newest published version, from the cache
+// when it is fresh, otherwise from the GitHub API (updating the cache). Any
+// failure yields "", never an error the caller has to handle: the check is
+// best-effort by design.
+func latestKnownVersion(ctx context.Context) string {
+	path, err := updateCachePath()
+	if err != nil {
+		return ""
+	}
+	var cache updateCheckCache
+	if raw, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(raw, &cache) == nil && time.Since(cache.CheckedAt) < updateCheckCacheTTL {
+			return cache.Latest
+		}
+	}
+	rel, err := LatestRelease(ctx)
+	if err != nil {
+		return ""
+	}
+	cache = updateCheckCache{CheckedAt: time.Now(), Latest: rel.Version()}
+	if raw, err := json.Marshal(cache); err == nil {
+		os.WriteFile(path, raw, 0o644)
+	}
+	return cache.Latest
+}
+
+// updateCheckDisabled reports whether the user opted out of the startup
+// release check.
+func updateCheckDisabled(cfg *Config) bool {
+	return cfg.NoUpdateCheck || os.Getenv("OPENHANDS_NO_UPDATE_CHECK") != ""
+}
+
+// startUpdateCheck kicks off the asynchronous release check. It never blocks
+// or fails startup: when a newer version is known, a single note is printed;
+// otherwise nothing happens.
+func startUpdateCheck(cfg *Config) {
+	if updateCheckDisabled(cfg) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		latest := latestKnownVersion(ctx)
+		if latest != "" && compareVersions(latest, Version) > 0 {
+			infof("A newer version %s is available (you have %s); run `openhands upgrade`.", latest, Version)
+		}
+	}()
+}
+
+// CmdVersion implements `openhands version`: it prints the launcher version,
+// and with -check also queries for the latest release, exiting 10 when a
+// newer one exists so scripts can detect outdated installs.
+func CmdVersion(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	check := fs.Bool("check", false, "also query GitHub for the latest release; exit 10 when outdated")
+	asJSON := fs.Bool("json", false, "emit the version as JSON")
+	fs.Parse(args)
+
+	out := versionJSON{Version: Version}
+	if !*asJSON {
+		fmt.Printf("openhands %s\n", Version)
+	}
+	if *check {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		rel, err := LatestRelease(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		out.Latest = rel.Version()
+		out.Outdated = compareVersions(out.Latest, Version) > 0
+		if !*asJSON {
+			fmt.Printf("latest    %s\n", out.Latest)
+		}
+	}
+	if *asJSON {
+		if err := printJSON(out); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	if out.Outdated {
+		return 10
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// CmdUpgrade implements `openhands upgrade`. By default it self-updates the
+// launcher binary; with --all it also pulls the matching app and runtime
+// images and migrates the config file schema.
+func CmdUpgrade(args []string) int {
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	all := fs.Bool("all", false, "also pull the matching images and migrate the config file")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	rel, err := LatestRelease(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	if compareVersions(Version, rel.Version()) >= 0 {
+		fmt.Printf("openhands %s is up to date\n", Version)
+	} else {
+		fmt.Printf("Updating openhands %s -> %s\n", Version, rel.Version())
+		if err := SelfUpdate(ctx, rel); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Updated to %s\n", rel.Version())
+		printChangelogSince(ctx, Version, rel)
+	}
+
+	if !*all {
+		return 0
+	}
+	if err := upgradeImages(ctx, rel.ImageTag()); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	migrated, err := MigrateConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	if migrated {
+		fmt.Println("Config file migrated to the current schema (previous file kept as cli.toml.bak)")
+	}
+	return 0
+}
+
+// upgradeImages pulls the app and runtime images for the given tag so the
+// next launch does not pay the download cost.
+func upgradeImages(ctx context.Context, tag string) error {
+	cli, err := NewDockerClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	if err := PingDaemon(ctx, cli); err != nil {
+		return err
+	}
+	cfg := &Config{Tag: tag}
+	for _, ref := range []string{cfg.AppImage(), cfg.RuntimeImage()} {
+		if err := withRetry(ctx, "pull "+ref, func() error {
+			return pullImage(ctx, cli, ref, "")
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printChangelogSince renders the notes of every release between the old
+// version and the one just installed, oldest first, so skipped releases are
+// not silently lost. Falls back to the new release's own notes when the list
+// cannot be fetched (rate limit, dev builds with no comparable version).
+func printChangelogSince(ctx context.Context, oldVersion string, rel *Release) {
+	rels, err := ReleasesBetween(ctx, oldVersion)
+	if err != nil || len(rels) == 0 {
+		printChangelog(rel)
+		return
+	}
+	for _, r := range rels {
+		printChangelog(r)
+	}
+}
+
+// printChangelog shows a concise version of the release notes: headings and
+// top-level bullet points, capped so the terminal is not flooded. Lines
+// announcing breaking or deprecated behavior are prefixed so they stand out
+// even in the trimmed view.
+func printChangelog(rel *Release) {
+	if strings.TrimSpace(rel.Body) == "" {
+		return
+	}
+	fmt.Printf("\nChanges in %s:\n", rel.TagName)
+	const maxLines = 20
+	shown := 0
+	for _, line := range strings.Split(rel.Body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if shown == maxLines {
+			fmt.Println("  ...")
+			break
+		}
+		if isBreakingNote(trimmed) {
+			fmt.Printf("  !! %s\n", trimmed)
+		} else {
+			fmt.Printf("  %s\n", trimmed)
+		}
+		shown++
+	}
+}
+
+// isBreakingNote flags changelog lines that likely require user action.
+func isBreakingNote(line string) bool {
+	lower := strings.ToLower(line)
+	for _, needle := range []string{"breaking", "deprecat", "removed flag", "renamed flag", "config migration"} {
+		if strings.Contains(lower, needle) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package internal
+
+import "testing"
+
+func TestIsBreakingNote(t *testing.T) {
+	breaking := []string{
+		"BREAKING: --workspace flag removed",
+		"The sandbox_image key is deprecated in favor of runtime_image",
+		"Renamed flag --tz to --timezone",
+	}
+	for _, line := range breaking {
+		if !isBreakingNote(line) {
+			t.Errorf("isBreakingNote(%q) = false, want true", line)
+		}
+	}
+	harmless := []string{
+		"Fix a crash when the workspace contains symlinks",
+		"* Faster image pulls",
+	}
+	for _, line := range harmless {
+		if isBreakingNote(line) {
+			t.Errorf("isBreakingNote(%q) = true, want false", line)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Version is the launcher's own version, overridden at release build time via
+//
+//	-ldflags "-X github.com/All-Hands-AI/OpenHands/launcher/internal.Version=..."
+var Version = "dev"
+
+// compareVersions compares two dot-separated version strings numerically,
+// ignoring a leading "v" and anything after a "-" (pre-release suffixes are
+// treated like the release they precede). It returns -1, 0 or 1. Unparseable
+// versions such as "dev" compare as older than everything.
+func compareVersions(a, b string) int {
+	pa, pb := parseVersion(a), parseVersion(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var na, nb int
+		if i < len(pa) {
+			na = pa[i]
+		}
+		if i < len(pb) {
+			nb = pb[i]
+		}
+		if na != nb {
+			if na < nb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return parts
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,22 @@
+package internal
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"0.62.0", "0.62.0", 0},
+		{"v0.62.0", "0.62", 0},
+		{"0.62.1", "0.62.0", 1},
+		{"0.9.0", "0.10.0", -1},
+		{"1.0.0-rc1", "1.0.0", 0},
+		{"dev", "0.62.0", -1},
+	}
+	for _, tc := range cases {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// vscodeURLPath is the app endpoint that reports the embedded VS Code
+// server's URL, where the image serves it.
+const vscodeURLPath = "/api/vscode-url"
+
+// waitVSCodeURL locates the runtime's embedded VS Code server for
+// -open-vscode. The app server is asked first, since it knows the exact
+// token-bearing URL; when the endpoint is missing (older images) the URL is
+// derived from the newest runtime container's published ports instead. The
+// runtime only starts with the first conversation, so the lookup polls with
+// the same backoff as server readiness until the startup timeout.
+func waitVSCodeURL(ctx context.Context, cli *client.Client, cfg *Config, baseURL string) (string, error) {
+	deadline := time.Now().Add(cfg.StartupTimeout)
+	backoff := 500 * time.Millisecond
+	for time.Now().Before(deadline) {
+		if u := fetchVSCodeURL(baseURL); u != "" {
+			return u, nil
+		}
+		if u := runtimeVSCodeURL(ctx, cli, cfg); u != "" {
+			return u, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff < 5*time.Second {
+			backoff *= 2
+		}
+	}
+	return "", fmt.Errorf("no VS Code server appeared within %s; it starts with the first conversation", cfg.StartupTimeout)
+}
+
+// fetchVSCodeURL asks the app server for the VS Code URL. Any failure just
+// means "not via the API": older images do not serve the endpoint, and the
+// runtime fallback still applies.
+func fetchVSCodeURL(baseURL string) string {
+	resp, err := probeClient.Get(baseURL + vscodeURLPath)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	var body struct {
+		URL string `json:"vscode_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(body.URL)
+}
+
+// runtimeVSCodeURL derives the VS Code URL from the newest runtime
+// container: among its published ports, the VS Code server is the one that
+// answers plain HTTP at its root - the runtime's action server 404s there,
+// which probeStatusUp counts as down.
+func runtimeVSCodeURL(ctx context.Context, cli *client.Client, cfg *Config) string {
+	containers, err := cli.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		return ""
+	}
+	var newest *types.Container
+	for i, c := range containers {
+		if strings.HasPrefix(containerName(c), "openhands-runtime-") &&
+			(newest == nil || c.Created >= newest.Created) {
+			newest = &containers[i]
+		}
+	}
+	if newest == nil {
+		return ""
+	}
+	for _, port := range publishedHostPorts(newest.Ports) {
+		url := fmt.Sprintf("http://%s:%d", cfg.uiHost(), port)
+		if probeEndpoint(url + "/") {
+			return url
+		}
+	}
+	return ""
+}
+
+// publishedHostPorts extracts a container's published TCP host ports,
+// sorted and deduplicated (dual-stack daemons report one binding per
+// address family).
+func publishedHostPorts(ports []types.Port) []int {
+	seen := map[int]bool{}
+	var out []int
+	for _, p := range ports {
+		if p.Type != "tcp" || p.PublicPort == 0 || seen[int(p.PublicPort)] {
+			continue
+		}
+		seen[int(p.PublicPort)] = true
+		out = append(out, int(p.PublicPort))
+	}
+	sort.Ints(out)
+	return out
+}
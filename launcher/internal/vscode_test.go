@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+)
+
+func TestPublishedHostPorts(t *testing.T) {
+	ports := []types.Port{
+		{PrivatePort: 8080, PublicPort: 34001, Type: "tcp", IP: "0.0.0.0"},
+		{PrivatePort: 8080, PublicPort: 34001, Type: "tcp", IP: "::"},
+		{PrivatePort: 9000, PublicPort: 34000, Type: "tcp", IP: "0.0.0.0"},
+		{PrivatePort: 53, PublicPort: 34002, Type: "udp", IP: "0.0.0.0"},
+		{PrivatePort: 7000, Type: "tcp"},
+	}
+	got := publishedHostPorts(ports)
+	want := []int{34000, 34001}
+	if len(got) != len(want) {
+		t.Fatalf("publishedHostPorts = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("publishedHostPorts = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestOpenURL(t *testing.T) {
+	cfg := &Config{Port: 3000}
+	if got := cfg.openURL(); got != "http://localhost:3000" {
+		t.Errorf("openURL without a path = %q", got)
+	}
+	cfg.OpenPath = "/settings"
+	if got := cfg.openURL(); got != "http://localhost:3000/settings" {
+		t.Errorf("openURL with a path = %q", got)
+	}
+}
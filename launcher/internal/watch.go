@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CmdWatch implements `openhands watch`: it sends the task to the running
+// instance's conversation and re-sends it whenever files in the workspace
+// change, a continuous "keep this property true" loop driven from the CLI:
+//
+//	openhands watch -task "keep the tests green" ./repo
+//
+// The agent edits the workspace too, so a change only re-triggers once the
+// agent has gone idle; otherwise its own edits would feed back into an
+// endless loop.
+func CmdWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	port := fs.Int("port", DefaultPort, "port of the running OpenHands instance")
+	conv := fs.String("conversation", "", "conversation ID (defaults to the most recent)")
+	task := fs.String("task", "", "message to (re-)send on changes (required)")
+	interval := fs.Duration("interval", 3*time.Second, "how often to check for changes")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openhands watch -task TEXT [flags] WORKSPACE\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *task == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	workspace, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+
+	client := newEventsClient(fmt.Sprintf("http://localhost:%d", *port))
+	convID := *conv
+	if convID == "" {
+		if convID, err = client.LatestConversation(); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+	}
+	if err := client.SendMessage(convID, *task); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Task sent; watching %s for changes (Ctrl-C stops).\n", workspace)
+
+	snapshot := snapshotWorkspace(workspace)
+	pending := false
+	for {
+		time.Sleep(*interval)
+		next := snapshotWorkspace(workspace)
+		if changed := diffSnapshots(snapshot, next); len(changed) > 0 {
+			snapshot = next
+			pending = true
+			fmt.Fprintf(os.Stderr, "Changed: %s\n", changed[0])
+		}
+		if !pending {
+			continue
+		}
+		if !agentIdle(client, convID) {
+			// The agent is still working - likely on the very change we
+			// noticed - so hold the re-trigger until it settles.
+			continue
+		}
+		if err := client.SendMessage(convID, *task); err != nil {
+			fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, "Workspace changed while the agent was idle; task re-sent.")
+		pending = false
+		snapshot = snapshotWorkspace(workspace)
+	}
+}
+
+// fileStamp is what we track per file to detect changes cheaply.
+type fileStamp struct {
+	size  int64
+	mtime time.Time
+}
+
+// snapshotWorkspace records size and mtime of every tracked file. It uses
+// the same file listing as the indexer, so .gitignored build output does not
+// cause spurious triggers in git repos.
+func snapshotWorkspace(workspace string) map[string]fileStamp {
+	files, err := listRepoFiles(workspace)
+	if err != nil {
+		return nil
+	}
+	snap := make(map[string]fileStamp, len(files))
+	for _, rel := range files {
+		info, err := os.Stat(filepath.Join(workspace, rel))
+		if err != nil {
+			continue
+		}
+		snap[rel] = fileStamp{size: info.Size(), mtime: info.ModTime()}
+	}
+	return snap
+}
+
+// diffSnapshots returns the paths that were added, removed or modified.
+func diffSnapshots(old, new map[string]fileStamp) []string {
+	var changed []string
+	for path, stamp := range new {
+		if prev, ok := old[path]; !ok || prev != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// agentIdle reports whether the conversation's agent is in a state that can
+// accept a new task. Errors count as busy, so transient API failures delay
+// the re-trigger instead of firing it blind.
+func agentIdle(client *eventsClient, convID string) bool {
+	events, err := client.Events(convID, -1)
+	if err != nil {
+		return false
+	}
+	switch computeMetrics(events, time.Now()).LastState {
+	case "awaiting_user_input", "finished", "stopped", "error":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	old := map[string]fileStamp{
+		"a.go": {size: 10, mtime: now},
+		"b.go": {size: 20, mtime: now},
+	}
+	same := map[string]fileStamp{
+		"a.go": {size: 10, mtime: now},
+		"b.go": {size: 20, mtime: now},
+	}
+	if changed := diffSnapshots(old, same); len(changed) != 0 {
+		t.Errorf("identical snapshots should not differ, got %v", changed)
+	}
+
+	edited := map[string]fileStamp{
+		"a.go": {size: 11, mtime: now},
+		"c.go": {size: 5, mtime: now},
+	}
+	changed := diffSnapshots(old, edited)
+	want := map[string]bool{"a.go": true, "b.go": true, "c.go": true}
+	if len(changed) != len(want) {
+		t.Fatalf("diffSnapshots = %v, want edit+add+delete", changed)
+	}
+	for _, path := range changed {
+		if !want[path] {
+			t.Errorf("unexpected change %q", path)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/term"
+)
+
+// prompter abstracts terminal prompting so the wizard logic is testable
+// without a real terminal. AskSecret reads without echo.
+type prompter interface {
+	Ask(question, def string) (string, error)
+	AskSecret(question string) (string, error)
+}
+
+// terminalPrompter prompts on the controlling terminal.
+type terminalPrompter struct {
+	reader *bufio.Reader
+}
+
+func newTerminalPrompter() *terminalPrompter {
+	return &terminalPrompter{reader: bufio.NewReader(os.Stdin)}
+}
+
+func (p *terminalPrompter) Ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", question)
+	}
+	line, err := p.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+func (p *terminalPrompter) AskSecret(question string) (string, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", question)
+	raw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// wizardModels are the choices offered by the setup wizard; the last entry
+// lets the user type any LiteLLM-style model name.
+var wizardModels = []struct {
+	Label string
+	Model string
+}{
+	{"Anthropic Claude Sonnet", "anthropic/claude-sonnet-4-20250514"},
+	{"OpenAI GPT-4o", "openai/gpt-4o"},
+	{"Local model (Ollama, LM Studio, vLLM)", ""},
+	{"Other (enter a model name)", ""},
+}
+
+// runWizard walks the user through the minimal LLM setup: model, API key
+// and, for proxies and local servers, a base URL. It returns the resulting
+// settings and whether the user wants them saved to the global config.
+func runWizard(p prompter) (LLMConfig, bool, error) {
+	var llm LLMConfig
+	fmt.Fprintln(os.Stderr, "No LLM is configured; the agent cannot work without one. Let's set it up.")
+	for i, m := range wizardModels {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, m.Label)
+	}
+	choice, err := p.Ask("Provider", "1")
+	if err != nil {
+		return llm, false, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(choice))
+	if err != nil || n < 1 || n > len(wizardModels) {
+		return llm, false, fmt.Errorf("invalid choice %q", choice)
+	}
+	llm.Model = wizardModels[n-1].Model
+	if llm.Model == "" {
+		if llm.Model, err = p.Ask("Model name (LiteLLM format, e.g. openai/local)", ""); err != nil {
+			return llm, false, err
+		}
+		if llm.Model == "" {
+			return llm, false, fmt.Errorf("a model name is required")
+		}
+	}
+	if llm.APIKey, err = p.AskSecret("API key (empty for local servers)"); err != nil {
+		return llm, false, err
+	}
+	if llm.BaseURL, err = p.Ask("Base URL (empty unless using a proxy or local server)", ""); err != nil {
+		return llm, false, err
+	}
+	save, err := p.Ask("Save these settings to ~/.openhands/cli.toml? (y/n)", "y")
+	if err != nil {
+		return llm, false, err
+	}
+	return llm, strings.HasPrefix(strings.ToLower(save), "y"), nil
+}
+
+// maybeRunWizard fills in the LLM settings interactively when nothing
+// configured a model and a human is at the terminal. In non-interactive
+// runs (or with -no-interactive) it only warns, so CI never blocks on a
+// prompt.
+func maybeRunWizard(cfg *Config) error {
+	if cfg.LLM.Model != "" {
+		return nil
+	}
+	if cfg.NoInteractive || !interactiveSession() {
+		fmt.Fprintln(os.Stderr, "Warning: no LLM model is configured; set -model/$LLM_MODEL or the config file, or the agent will not work.")
+		return nil
+	}
+	llm, save, err := runWizard(newTerminalPrompter())
+	if err != nil {
+		return fmt.Errorf("setup wizard: %w", err)
+	}
+	cfg.LLM.Model = llm.Model
+	if llm.APIKey != "" {
+		cfg.LLM.APIKey = llm.APIKey
+	}
+	if llm.BaseURL != "" {
+		cfg.LLM.BaseURL = llm.BaseURL
+	}
+	if save {
+		if err := saveGlobalLLM(cfg.LLM); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "Saved. Future launches will use these settings.")
+	}
+	return nil
+}
+
+// saveGlobalLLM writes the LLM settings into the global config file,
+// preserving whatever else it contains.
+func saveGlobalLLM(llm LLMConfig) error {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return err
+	}
+	fc, err := loadFileConfig(path)
+	if err != nil {
+		return err
+	}
+	fc.LLM.Model = llm.Model
+	fc.LLM.APIKey = llm.APIKey
+	fc.LLM.BaseURL = llm.BaseURL
+	if fc.SchemaVersion == 0 {
+		fc.SchemaVersion = configSchemaVersion
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return toml.NewEncoder(f).Encode(fc)
+}
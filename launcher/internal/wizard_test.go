@@ -0,0 +1,76 @@
+package internal
+
+import "testing"
+
+// scriptedPrompter feeds canned answers to the wizard.
+type scriptedPrompter struct {
+	answers []string
+	secrets []string
+}
+
+func (p *scriptedPrompter) Ask(question, def string) (string, error) {
+	if len(p.answers) == 0 {
+		return def, nil
+	}
+	a := p.answers[0]
+	p.answers = p.answers[1:]
+	if a == "" {
+		return def, nil
+	}
+	return a, nil
+}
+
+func (p *scriptedPrompter) AskSecret(string) (string, error) {
+	if len(p.secrets) == 0 {
+		return "", nil
+	}
+	s := p.secrets[0]
+	p.secrets = p.secrets[1:]
+	return s, nil
+}
+
+func TestRunWizard(t *testing.T) {
+	p := &scriptedPrompter{
+		answers: []string{"2", "http://localhost:4000", "n"},
+		secrets: []string{"sk-test"},
+	}
+	llm, save, err := runWizard(p)
+	if err != nil {
+		t.Fatalf("runWizard: %v", err)
+	}
+	if llm.Model != "openai/gpt-4o" {
+		t.Errorf("model = %q", llm.Model)
+	}
+	if llm.APIKey != "sk-test" {
+		t.Errorf("api key = %q", llm.APIKey)
+	}
+	if llm.BaseURL != "http://localhost:4000" {
+		t.Errorf("base url = %q", llm.BaseURL)
+	}
+	if save {
+		t.Errorf("save should be false for answer n")
+	}
+}
+
+func TestRunWizardCustomModel(t *testing.T) {
+	p := &scriptedPrompter{
+		answers: []string{"4", "openrouter/some/model", "", "y"},
+	}
+	llm, save, err := runWizard(p)
+	if err != nil {
+		t.Fatalf("runWizard: %v", err)
+	}
+	if llm.Model != "openrouter/some/model" {
+		t.Errorf("model = %q", llm.Model)
+	}
+	if !save {
+		t.Errorf("save should be true for answer y")
+	}
+}
+
+func TestRunWizardRejectsBadChoice(t *testing.T) {
+	p := &scriptedPrompter{answers: []string{"9"}}
+	if _, _, err := runWizard(p); err == nil {
+		t.Fatal("expected an error for an out-of-range choice")
+	}
+}
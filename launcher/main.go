@@ -0,0 +1,111 @@
+// Command openhands launches a local OpenHands instance in Docker.
+//
+// Usage:
+//
+//	openhands [flags] [WORKSPACE]
+//	openhands <command> [args]
+//
+// WORKSPACE is the directory the agent works in; it is mounted into the
+// sandbox container and defaults to the current directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/All-Hands-AI/OpenHands/launcher/internal"
+)
+
+// commands maps subcommand names to their implementations. Anything not
+// listed here is treated as the default launch invocation.
+var commands = map[string]func(args []string) int{
+	"upgrade":    internal.CmdUpgrade,
+	"uninstall":  internal.CmdUninstall,
+	"shell-init": internal.CmdShellInit,
+	"completion": internal.CmdShellInit,
+	"__complete": internal.CmdComplete,
+	"audit":      internal.CmdAudit,
+	"share":      internal.CmdShare,
+	"play":       internal.CmdPlay,
+	"view":       internal.CmdView,
+	"logs":       internal.CmdLogs,
+	"events":     internal.CmdEvents,
+	"run":        internal.CmdRun,
+	"init":       internal.CmdInit,
+	"index":      internal.CmdIndex,
+	"pool":       internal.CmdPool,
+	"pause":      internal.CmdPause,
+	"resume":     internal.CmdResume,
+	"stop-task":  internal.CmdStopTask,
+	"feedback":   internal.CmdFeedback,
+	"status":     internal.CmdStatus,
+	"watch":      internal.CmdWatch,
+	"archive":    internal.CmdArchive,
+	"stop":       internal.CmdStop,
+	"ps":         internal.CmdPs,
+	"version":    internal.CmdVersion,
+	"doctor":     internal.CmdDoctor,
+	"clean":      internal.CmdClean,
+	"config":     internal.CmdConfig,
+	"auth":       internal.CmdAuth,
+	"pull":       internal.CmdPull,
+	"compose":    internal.CmdCompose,
+	"shell":      internal.CmdShell,
+	"attach":     internal.CmdShell,
+}
+
+func init() {
+	internal.Subcommands = func() []string {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		return names
+	}
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd(os.Args[2:]))
+		}
+	}
+	launch()
+}
+
+func launch() {
+	cfg := &internal.Config{}
+	internal.RegisterLaunchFlags(flag.CommandLine, cfg)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [WORKSPACE] [-- DOCKER-ARGS...]\n\nWORKSPACE defaults to the current directory. Anything after -- is\npassed to the app container as extra docker run options, like\n-docker-arg.\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	// Everything after a bare -- goes to docker, not to our flag parsing.
+	args := os.Args[1:]
+	for i, arg := range args {
+		if arg == "--" {
+			cfg.DockerArgs = append(cfg.DockerArgs, args[i+1:]...)
+			args = args[:i]
+			break
+		}
+	}
+	flag.CommandLine.Parse(args)
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cfg.Workspace = flag.Arg(0)
+
+	if err := internal.FinalizeConfig(cfg, flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		os.Exit(1)
+	}
+	if err := internal.Run(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "openhands: %v\n", err)
+		// The container's own exit status passes through, so scripts can
+		// distinguish a server crash from a launcher failure.
+		os.Exit(internal.ExitCode(err))
+	}
+}